@@ -0,0 +1,158 @@
+package multielo
+
+import (
+	"fmt"
+	"time"
+)
+
+// AchievementKind names a type of milestone.
+type AchievementKind string
+
+const (
+	// AchievementFirstWin is a player's first ever win.
+	AchievementFirstWin AchievementKind = "first_win"
+	// AchievementWinStreak is a run of 5, 10 or 20 consecutive wins.
+	AchievementWinStreak AchievementKind = "win_streak"
+	// AchievementNewPeak is a rating higher than the player has had before
+	// this season, once they have played newPeakMatches matches and their
+	// rating is no longer provisional.
+	AchievementNewPeak AchievementKind = "new_peak"
+	// AchievementMatchesPlayed is a career match count reaching 10, 50,
+	// 100, 250, 500 or 1000.
+	AchievementMatchesPlayed AchievementKind = "matches_played"
+	// AchievementGiantKiller is finishing ahead of an opponent rated at
+	// least 200 higher going into the match.
+	AchievementGiantKiller AchievementKind = "giant_killer"
+)
+
+const (
+	// giantKillingGap is how much higher an opponent's rating must be for
+	// beating them to count as an upset.
+	giantKillingGap = 200
+
+	// newPeakMatches is how many matches a player must have played before a
+	// new peak counts, so a newcomer climbing to their level doesn't earn
+	// one nearly every match.
+	newPeakMatches = 10
+)
+
+var (
+	winStreakMilestones     = []int{5, 10, 20}
+	matchesPlayedMilestones = []int{10, 50, 100, 250, 500, 1000}
+)
+
+// Achievement is a milestone a player reached in a match.
+type Achievement struct {
+	Kind    AchievementKind
+	MatchID int
+	Date    time.Time
+
+	// Description says what was achieved, ready to show to players.
+	Description string
+}
+
+// detectAchievements returns the achievements a player earned with result,
+// which has already been applied to their stats. previousPeak is their peak
+// rating before the match.
+func (l *League) detectAchievements(player *Player, result *MatchResult, match *Match, previousPeak int) []Achievement {
+	achievements := []Achievement{}
+	add := func(kind AchievementKind, description string) {
+		achievements = append(achievements, Achievement{
+			Kind:        kind,
+			MatchID:     match.ID,
+			Date:        match.Date,
+			Description: description,
+		})
+	}
+
+	if result.Position == 1 && !player.hasAchievement(AchievementFirstWin) {
+		add(AchievementFirstWin, "won for the first time")
+	}
+
+	for _, milestone := range winStreakMilestones {
+		if player.Stats.WinStreak == milestone {
+			add(AchievementWinStreak, fmt.Sprintf("won %d in a row", milestone))
+		}
+	}
+
+	played := l.archivedMatches(player) + player.Stats.MatchesPlayed
+	if player.ELO > previousPeak && played >= newPeakMatches && !l.IsProvisional(player) {
+		add(AchievementNewPeak, fmt.Sprintf("reached a new peak rating of %d", player.ELO))
+	}
+
+	for _, milestone := range matchesPlayedMilestones {
+		if played == milestone {
+			add(AchievementMatchesPlayed, fmt.Sprintf("played %d matches", milestone))
+		}
+	}
+
+	// only the biggest upset in a match counts
+	var giant *MatchResult
+	for _, opponent := range match.Results {
+		if opponent.Player == nil || opponent.Position <= result.Position {
+			continue
+		}
+
+		if opponent.ELOBefore-result.ELOBefore >= giantKillingGap && (giant == nil || opponent.ELOBefore > giant.ELOBefore) {
+			giant = opponent
+		}
+	}
+
+	if giant != nil {
+		add(AchievementGiantKiller, fmt.Sprintf("beat %s, rated %d higher", giant.Player.Name, giant.ELOBefore-result.ELOBefore))
+	}
+
+	return achievements
+}
+
+// hasAchievement reports whether the player has ever earned an achievement
+// of the given kind.
+func (p *Player) hasAchievement(kind AchievementKind) bool {
+	for _, achievement := range p.Achievements {
+		if achievement.Kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// archivedMatches counts the player's results in closed seasons. Closed
+// seasons don't change, so each is counted once, when it is first needed.
+func (l *League) archivedMatches(p *Player) int {
+	if l.archived == nil || l.archivedSeasons > len(l.Seasons) {
+		l.archived = map[string]int{}
+		l.archivedSeasons = 0
+	}
+
+	for _, season := range l.Seasons[l.archivedSeasons:] {
+		for _, match := range season.Matches {
+			for _, result := range match.Results {
+				if result.Player != nil {
+					l.archived[result.Player.Name]++
+				}
+			}
+		}
+	}
+	l.archivedSeasons = len(l.Seasons)
+
+	return l.archived[p.Name]
+}
+
+// withoutMatches returns the achievements that weren't earned in any of the
+// given matches.
+func withoutMatches(achievements []Achievement, matches []Match) []Achievement {
+	ids := map[int]bool{}
+	for _, match := range matches {
+		ids[match.ID] = true
+	}
+
+	kept := []Achievement{}
+	for _, achievement := range achievements {
+		if !ids[achievement.MatchID] {
+			kept = append(kept, achievement)
+		}
+	}
+
+	return kept
+}
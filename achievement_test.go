@@ -0,0 +1,138 @@
+package multielo_test
+
+import (
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+// kinds returns the kind of each achievement, in order.
+func kinds(achievements []multielo.Achievement) []multielo.AchievementKind {
+	out := []multielo.AchievementKind{}
+	for _, achievement := range achievements {
+		out = append(out, achievement.Kind)
+	}
+
+	return out
+}
+
+func TestLeague_Achievements(t *testing.T) {
+	t.Run("FirstWin", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+
+		var announced []multielo.Achievement
+		l.OnAchievement = func(p *multielo.Player, a multielo.Achievement) {
+			assert.Equal(t, "alice", p.Name)
+			assert.Equal(t, l.Matches[len(l.Matches)-1].ID, a.MatchID)
+			announced = append(announced, a)
+		}
+
+		recordMatch(t, l, "alice", "bob")
+
+		alice, _ := l.GetPlayer("alice")
+		assert.Equal(t, []multielo.AchievementKind{multielo.AchievementFirstWin}, kinds(alice.Achievements))
+		assert.Equal(t, alice.Achievements, announced)
+		assert.Equal(t, l.Matches[0].ID, alice.Achievements[0].MatchID)
+		assert.Equal(t, l.Matches[0].Date, alice.Achievements[0].Date)
+
+		bob, _ := l.GetPlayer("bob")
+		assert.Empty(t, bob.Achievements)
+
+		// a second win isn't a first win
+		recordMatch(t, l, "alice", "bob")
+		assert.Len(t, announced, 1)
+	})
+
+	t.Run("NewPeak", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		for i := 0; i < 12; i++ {
+			recordMatch(t, l, "alice", "bob")
+		}
+
+		// peaks only count from the tenth match
+		alice, _ := l.GetPlayer("alice")
+		peaks := []int{}
+		for _, achievement := range alice.Achievements {
+			if achievement.Kind == multielo.AchievementNewPeak {
+				peaks = append(peaks, achievement.MatchID)
+			}
+		}
+		assert.Equal(t, []int{l.Matches[9].ID, l.Matches[10].ID, l.Matches[11].ID}, peaks)
+
+		// or once the player's rating is no longer provisional
+		l = newTestLeague(t, "alice", "bob")
+		l.Config.Provisional = &multielo.ProvisionalPolicy{Matches: 12}
+		for i := 0; i < 12; i++ {
+			recordMatch(t, l, "alice", "bob")
+		}
+
+		alice, _ = l.GetPlayer("alice")
+		assert.Equal(t, multielo.AchievementNewPeak, alice.Achievements[len(alice.Achievements)-1].Kind)
+		assert.Equal(t, l.Matches[11].ID, alice.Achievements[len(alice.Achievements)-1].MatchID)
+		assert.NotContains(t, kinds(alice.Achievements[:len(alice.Achievements)-1]), multielo.AchievementNewPeak)
+	})
+
+	t.Run("Milestones", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		for i := 0; i < 10; i++ {
+			recordMatch(t, l, "alice", "bob")
+		}
+
+		alice, _ := l.GetPlayer("alice")
+		assert.Contains(t, kinds(alice.Achievements), multielo.AchievementWinStreak)
+		assert.Contains(t, kinds(alice.Achievements), multielo.AchievementMatchesPlayed)
+
+		var descriptions []string
+		for _, achievement := range alice.Achievements {
+			descriptions = append(descriptions, achievement.Description)
+		}
+		assert.Contains(t, descriptions, "won 5 in a row")
+		assert.Contains(t, descriptions, "won 10 in a row")
+		assert.Contains(t, descriptions, "played 10 matches")
+	})
+
+	t.Run("GiantKiller", func(t *testing.T) {
+		l := newTestLeague(t, "rookie")
+		assert.NoError(t, l.AddPlayerWithRating("champ", 1250))
+
+		recordMatch(t, l, "rookie", "champ")
+
+		rookie, _ := l.GetPlayer("rookie")
+		assert.Contains(t, kinds(rookie.Achievements), multielo.AchievementGiantKiller)
+		assert.Equal(t, "beat champ, rated 250 higher", rookie.Achievements[len(rookie.Achievements)-1].Description)
+	})
+
+	t.Run("UndoAndRecalculate", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		recordMatch(t, l, "alice", "bob")
+		recordMatch(t, l, "bob", "alice")
+
+		bob, _ := l.GetPlayer("bob")
+		assert.Contains(t, kinds(bob.Achievements), multielo.AchievementFirstWin)
+
+		_, err := l.UndoLastMatch()
+		assert.NoError(t, err)
+		assert.Empty(t, bob.Achievements)
+
+		recordMatch(t, l, "bob", "alice")
+		before := append([]multielo.Achievement{}, bob.Achievements...)
+
+		called := false
+		l.OnAchievement = func(*multielo.Player, multielo.Achievement) { called = true }
+		assert.NoError(t, l.Recalculate())
+		assert.Equal(t, before, bob.Achievements)
+		assert.False(t, called)
+	})
+
+	t.Run("MatchIDs", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		recordMatch(t, l, "alice", "bob")
+		_, err := l.CloseSeason("january", 1)
+		assert.NoError(t, err)
+		recordMatch(t, l, "alice", "bob")
+
+		assert.Equal(t, 1, l.Seasons[0].Matches[0].ID)
+		assert.Equal(t, 2, l.Matches[0].ID)
+	})
+}
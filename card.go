@@ -0,0 +1,202 @@
+package multielo
+
+import (
+	"image/color"
+	"io"
+	"strconv"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// The layout of the leaderboard card, from the left edge of the image.
+const (
+	cardWidth     = 16 * vg.Centimeter
+	cardMargin    = 0.6 * vg.Centimeter
+	cardTitle     = 1.4 * vg.Centimeter
+	cardRowHeight = 0.9 * vg.Centimeter
+
+	cardRankX     = 1.4 * vg.Centimeter
+	cardNameX     = 2 * vg.Centimeter
+	cardNameWidth = 6 * vg.Centimeter
+	cardELOX      = 9.6 * vg.Centimeter
+	cardChangeX   = 11.8 * vg.Centimeter
+	cardFormX     = 12.6 * vg.Centimeter
+	cardFormWidth = 2.4 * vg.Centimeter
+)
+
+var (
+	cardBackground = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	cardStripe     = color.RGBA{R: 242, G: 242, B: 242, A: 255}
+	cardText       = color.RGBA{R: 33, G: 33, B: 33, A: 255}
+	cardMuted      = color.RGBA{R: 117, G: 117, B: 117, A: 255}
+	cardGain       = color.RGBA{R: 46, G: 125, B: 50, A: 255}
+	cardLoss       = color.RGBA{R: 198, G: 40, B: 40, A: 255}
+)
+
+// WriteLeaderboardImage draws the leaderboard as an image to w in the given
+// format, such as "png" or "svg". Each row shows the player's rank, name,
+// rating, change since the last match and a sparkline of their recent
+// finishes, higher being better.
+func (l *League) WriteLeaderboardImage(w io.Writer, format string) error {
+	rows := l.LeaderboardSince(time.Time{})
+	if len(rows) == 0 {
+		return ErrNoPlayers
+	}
+
+	styles, err := l.playerStyles()
+	if err != nil {
+		return err
+	}
+
+	height := 2*cardMargin + cardTitle + vg.Length(len(rows)+1)*cardRowHeight
+	c, err := draw.NewFormattedCanvas(cardWidth, height, format)
+	if err != nil {
+		return err
+	}
+
+	dc := draw.New(c)
+	dc.FillPolygon(cardBackground, rectangle(0, 0, cardWidth, height))
+
+	title := cardTextStyle(18, draw.XLeft)
+	dc.FillText(title, vg.Point{X: cardMargin, Y: height - cardMargin - cardTitle/2}, "Leaderboard")
+
+	// rowY returns the middle of row i, where the header is row 0
+	rowY := func(i int) vg.Length {
+		return height - cardMargin - cardTitle - vg.Length(i)*cardRowHeight - cardRowHeight/2
+	}
+
+	header := cardTextStyle(10, draw.XRight)
+	header.Color = cardMuted
+	dc.FillText(header, vg.Point{X: cardRankX, Y: rowY(0)}, "#")
+	dc.FillText(header, vg.Point{X: cardELOX, Y: rowY(0)}, "ELO")
+	dc.FillText(header, vg.Point{X: cardChangeX, Y: rowY(0)}, "Change")
+	header.XAlign = draw.XLeft
+	dc.FillText(header, vg.Point{X: cardNameX, Y: rowY(0)}, "Player")
+	dc.FillText(header, vg.Point{X: cardFormX, Y: rowY(0)}, "Form")
+
+	for i, row := range rows {
+		y := rowY(i + 1)
+		if i%2 == 0 {
+			dc.FillPolygon(cardStripe, rectangle(cardMargin, y-cardRowHeight/2, cardWidth-cardMargin, y+cardRowHeight/2))
+		}
+
+		number := cardTextStyle(12, draw.XRight)
+		dc.FillText(number, vg.Point{X: cardRankX, Y: y}, strconv.Itoa(row.Rank))
+		dc.FillText(number, vg.Point{X: cardELOX, Y: y}, l.FormatELO(row.Player))
+
+		name := cardTextStyle(12, draw.XLeft)
+		dc.FillText(name, vg.Point{X: cardNameX, Y: y}, truncate(name, row.Player.Name, cardNameWidth))
+
+		drawChange(dc, vg.Point{X: cardChangeX, Y: y}, row.Change)
+		drawSparkline(dc, vg.Point{X: cardFormX, Y: y}, row.Player.Stats, styles[row.Player].color)
+	}
+
+	_, err = c.WriteTo(w)
+	return err
+}
+
+// cardTextStyle returns the style of text on the leaderboard card.
+func cardTextStyle(size vg.Length, align draw.XAlignment) draw.TextStyle {
+	return draw.TextStyle{
+		Color:   cardText,
+		Font:    font.From(plot.DefaultFont, size),
+		XAlign:  align,
+		YAlign:  draw.YCenter,
+		Handler: plot.DefaultTextHandler,
+	}
+}
+
+// drawChange draws a rating change right aligned at pt, with a triangle
+// pointing up for a gain or down for a loss.
+func drawChange(dc draw.Canvas, pt vg.Point, change int) {
+	style := cardTextStyle(12, draw.XRight)
+
+	switch {
+	case change > 0:
+		style.Color = cardGain
+		dc.FillText(style, pt, "+"+strconv.Itoa(change))
+	case change < 0:
+		style.Color = cardLoss
+		dc.FillText(style, pt, strconv.Itoa(change))
+	default:
+		style.Color = cardMuted
+		dc.FillText(style, pt, "–")
+		return
+	}
+
+	// the triangle sits to the left of the number
+	size := 0.12 * vg.Centimeter
+	x := pt.X - style.Width(strconv.Itoa(max(change, -change))+"+") - 2*size
+	tip, base := pt.Y+size, pt.Y-size
+	if change < 0 {
+		tip, base = base, tip
+	}
+	dc.FillPolygon(style.Color, []vg.Point{{X: x - size, Y: base}, {X: x + size, Y: base}, {X: x, Y: tip}})
+}
+
+// drawSparkline draws the player's recent finishes from pt, each scaled by
+// the size of the field so a win is at the top and last place at the bottom.
+// Wins are marked with a filled dot.
+func drawSparkline(dc draw.Canvas, pt vg.Point, stats *PlayerStats, clr color.Color) {
+	if stats == nil || len(stats.Last5Finish) == 0 {
+		style := cardTextStyle(12, draw.XLeft)
+		style.Color = cardMuted
+		dc.FillText(style, pt, "–")
+		return
+	}
+
+	finishes := stats.Last5Finish
+	span := 0.25 * cardRowHeight
+	step := cardFormWidth
+	if len(finishes) > 1 {
+		step = cardFormWidth / vg.Length(len(finishes)-1)
+	}
+
+	points := make([]vg.Point, len(finishes))
+	for i, position := range finishes {
+		// a field of one is a win
+		score := 1.0
+		if i < len(stats.Last5FieldSize) && stats.Last5FieldSize[i] > 1 {
+			n := stats.Last5FieldSize[i]
+			score = float64(n-position) / float64(n-1)
+		}
+
+		points[i] = vg.Point{X: pt.X + vg.Length(i)*step, Y: pt.Y - span + 2*span*vg.Length(score)}
+	}
+
+	line := draw.LineStyle{Color: clr, Width: vg.Points(1.5)}
+	if len(points) > 1 {
+		dc.StrokeLines(line, points)
+	}
+
+	for i, point := range points {
+		glyph := draw.GlyphStyle{Color: clr, Radius: vg.Points(2.5), Shape: draw.RingGlyph{}}
+		if finishes[i] == 1 {
+			glyph.Shape = draw.CircleGlyph{}
+		}
+		dc.DrawGlyphNoClip(glyph, point)
+	}
+}
+
+// truncate shortens s with an ellipsis so it fits in width.
+func truncate(style draw.TextStyle, s string, width vg.Length) string {
+	if style.Width(s) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 && style.Width(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+
+	return string(runes) + "…"
+}
+
+// rectangle returns the corners of a rectangle.
+func rectangle(x0, y0, x1, y1 vg.Length) []vg.Point {
+	return []vg.Point{{X: x0, Y: y0}, {X: x1, Y: y0}, {X: x1, Y: y1}, {X: x0, Y: y1}}
+}
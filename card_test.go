@@ -0,0 +1,56 @@
+package multielo_test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeague_WriteLeaderboardImage(t *testing.T) {
+	l := multielo.NewLeague()
+
+	var buf bytes.Buffer
+	assert.ErrorIs(t, l.WriteLeaderboardImage(&buf, "png"), multielo.ErrNoPlayers)
+
+	l = newTestLeague(t, "alice", "bob", "carol", "a player with a very long name indeed")
+	recordMatch(t, l, "alice", "bob", "carol")
+	recordMatch(t, l, "carol", "alice", "bob")
+
+	t.Run("PNG", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, l.WriteLeaderboardImage(&buf, "png"))
+
+		img, err := png.Decode(&buf)
+		assert.NoError(t, err)
+
+		// one row for each player grows the card
+		small := newTestLeague(t, "alice")
+		var smallBuf bytes.Buffer
+		assert.NoError(t, small.WriteLeaderboardImage(&smallBuf, "png"))
+		smallImg, err := png.Decode(&smallBuf)
+		assert.NoError(t, err)
+		assert.Equal(t, img.Bounds().Dx(), smallImg.Bounds().Dx())
+		assert.Greater(t, img.Bounds().Dy(), smallImg.Bounds().Dy())
+	})
+
+	t.Run("SVG", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, l.WriteLeaderboardImage(&buf, "svg"))
+
+		svg := buf.String()
+		assert.True(t, strings.HasPrefix(strings.TrimSpace(svg), "<?xml"))
+		assert.Contains(t, svg, "Leaderboard")
+		assert.Contains(t, svg, "carol")
+		assert.Contains(t, svg, "+17")
+		assert.Contains(t, svg, "…")
+	})
+
+	t.Run("UnknownFormat", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.Error(t, l.WriteLeaderboardImage(&buf, "gif"))
+	})
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/distrobyte/multielo"
+)
+
+// cli runs commands against the league file at path.
+type cli struct {
+	path   string
+	stdout io.Writer
+}
+
+func (c *cli) init(args []string) error {
+	flags := newFlagSet("init")
+	configPath := flags.String("config", "", "JSON or YAML config file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(c.path); err == nil {
+		return fmt.Errorf("%s already exists", c.path)
+	}
+
+	opts := []multielo.Option{}
+	if *configPath != "" {
+		config, err := multielo.LoadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, multielo.WithConfig(config))
+	}
+
+	if err := c.save(multielo.NewLeague(opts...)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stdout, "created %s\n", c.path)
+	return nil
+}
+
+func (c *cli) player(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: multielo player add|rm|ls")
+	}
+
+	switch args[0] {
+	case "add":
+		flags := newFlagSet("player add")
+		elo := flags.Int("elo", 0, "starting rating (default the league's)")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return errors.New("usage: multielo player add [-elo n] <name>")
+		}
+
+		// a rating given with -elo is passed on, even 0, for the league to
+		// check, so tell it apart from no rating at all
+		eloSet := false
+		flags.Visit(func(f *flag.Flag) {
+			eloSet = eloSet || f.Name == "elo"
+		})
+
+		return c.update(func(l *multielo.League) error {
+			if eloSet {
+				return l.AddPlayerWithRating(flags.Arg(0), *elo)
+			}
+			return l.AddPlayer(flags.Arg(0))
+		})
+	case "rm":
+		if len(args) != 2 {
+			return errors.New("usage: multielo player rm <name>")
+		}
+
+		return c.update(func(l *multielo.League) error {
+			return l.RemovePlayer(args[1])
+		})
+	case "ls":
+		l, err := c.load()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tELO\tPLAYED\tSTATUS")
+		for _, p := range l.GetPlayers() {
+			status := "active"
+			if p.Retired {
+				status = "retired"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", p.Name, l.FormatELO(p), p.Stats.MatchesPlayed, status)
+		}
+		return w.Flush()
+	}
+
+	return fmt.Errorf("unknown player command %q", args[0])
+}
+
+func (c *cli) match(args []string) error {
+	if len(args) != 2 || args[0] != "add" {
+		return errors.New(`usage: multielo match add "alice,bob=carol,dave"`)
+	}
+
+	// places are separated by commas and tied players by "="
+	places := [][]string{}
+	for _, place := range strings.Split(args[1], ",") {
+		places = append(places, strings.Split(place, "="))
+	}
+
+	return c.update(func(l *multielo.League) error {
+		diffs, err := l.AddMatchPlaces(places)
+		if err != nil {
+			return err
+		}
+
+		return l.WriteMatchDiffs(c.stdout, multielo.TableText, diffs)
+	})
+}
+
+func (c *cli) leaderboard(args []string) error {
+	flags := newFlagSet("leaderboard")
+	format := flags.String("format", "text", "text, markdown or html")
+	since := flags.String("since", "", "show changes since this date (default the last match)")
+	out := flags.String("o", "", "draw the leaderboard to a .png or .svg file instead")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: multielo leaderboard [-format text|markdown|html] [-since 2006-01-02] [-o file.png]")
+	}
+
+	date, err := parseDate(*since)
+	if err != nil {
+		return err
+	}
+
+	l, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	if *out != "" {
+		return c.writeImage(*out, l.WriteLeaderboardImage)
+	}
+
+	return l.WriteLeaderboard(c.stdout, multielo.TableFormat(*format), date)
+}
+
+func (c *cli) stats(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: multielo stats <name>")
+	}
+
+	l, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	p, err := l.GetPlayer(args[0])
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "name\t%s\n", p.Name)
+	fmt.Fprintf(w, "elo\t%s\n", l.FormatELO(p))
+	fmt.Fprintf(w, "peak\t%d\n", p.Stats.PeakELO)
+	fmt.Fprintf(w, "played\t%d\n", p.Stats.MatchesPlayed)
+	fmt.Fprintf(w, "won\t%d\n", p.Stats.MatchesWon)
+	fmt.Fprintf(w, "average place\t%.2f\n", p.Stats.AveragePlace())
+	fmt.Fprintf(w, "recent finishes\t%s\n", strings.Trim(fmt.Sprint(p.Stats.Last5Finish), "[]"))
+	fmt.Fprintf(w, "form\t%.2f\n", p.Stats.FormScore)
+	fmt.Fprintf(w, "win streak\t%d (best %d)\n", p.Stats.WinStreak, p.Stats.LongestWinStreak)
+	fmt.Fprintf(w, "podium streak\t%d (best %d)\n", p.Stats.PodiumStreak, p.Stats.LongestPodiumStreak)
+	return w.Flush()
+}
+
+func (c *cli) graph(args []string) error {
+	flags := newFlagSet("graph")
+	out := flags.String("o", "elo.png", "output file, .png or .svg")
+	player := flags.String("player", "", "draw only this player's history")
+	median := flags.Bool("median", false, "compare the player with the league median")
+	byDate := flags.Bool("dates", false, "plot against match dates rather than race numbers")
+	players := flags.String("players", "", "comma separated players to draw")
+	top := flags.Int("top", 0, "draw only the top n players")
+	from := flags.String("from", "", "draw matches from this date")
+	to := flags.String("to", "", "draw matches before this date")
+	season := flags.String("season", "", "draw a closed season")
+	labels := flags.Int("labels", 3, "label ratings every n races, 0 for none")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	opts := []multielo.GraphOption{multielo.GraphTop(*top), multielo.GraphLabelEvery(*labels)}
+	if *median {
+		opts = append(opts, multielo.GraphMedian())
+	}
+	if *byDate {
+		opts = append(opts, multielo.GraphByDate())
+	}
+	if *players != "" {
+		opts = append(opts, multielo.GraphPlayers(strings.Split(*players, ",")...))
+	}
+	if *season != "" {
+		opts = append(opts, multielo.GraphSeason(*season))
+	}
+	if *from != "" || *to != "" {
+		start, err := parseDate(*from)
+		if err != nil {
+			return err
+		}
+		end, err := parseDate(*to)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, multielo.GraphBetween(start, end))
+	}
+
+	l, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	return c.writeImage(*out, func(w io.Writer, format string) error {
+		if *player != "" {
+			return l.WritePlayerGraph(w, format, *player, opts...)
+		}
+		return l.WriteGraph(w, format, opts...)
+	})
+}
+
+func (c *cli) recalc(args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: multielo recalc")
+	}
+
+	return c.update(func(l *multielo.League) error {
+		return l.Recalculate()
+	})
+}
+
+// writeImage draws an image to the file at path with draw, in the format
+// given by the file's extension.
+func (c *cli) writeImage(path string, draw func(w io.Writer, format string) error) error {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if format != "png" && format != "svg" {
+		return fmt.Errorf("can't draw to %s: use a .png or .svg file", path)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := draw(f, format); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stdout, "wrote %s\n", path)
+	return nil
+}
+
+// load reads the league file.
+func (c *cli) load() (*multielo.League, error) {
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s doesn't exist: run multielo init first", c.path)
+	} else if err != nil {
+		return nil, err
+	}
+
+	l := &multielo.League{}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", c.path, err)
+	}
+
+	l.SetActor(os.Getenv("USER"))
+	return l, nil
+}
+
+// save writes the league file in the league's JSON schema, log included,
+// replacing it in one step so a failed write can't leave it half written.
+func (c *cli) save(l *multielo.League) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, c.path)
+}
+
+// update loads the league, changes it with fn and saves it if fn succeeds.
+func (c *cli) update(fn func(*multielo.League) error) error {
+	l, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(l); err != nil {
+		return err
+	}
+
+	return c.save(l)
+}
+
+// parseDate parses a YYYY-MM-DD date, or returns the zero time for "".
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD", s)
+	}
+
+	return date, nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return flags
+}
@@ -0,0 +1,86 @@
+// Command multielo manages a league stored in a file.
+//
+// Usage:
+//
+//	multielo [-f league.json] <command> [arguments]
+//
+// Commands:
+//
+//	init [-config file]          create a new league file
+//	player add [-elo n] <name>   add a player
+//	player rm <name>             retire a player
+//	player ls                    list every player
+//	match add <places>           record a match, e.g. "alice,bob,carol";
+//	                             join tied players with "=", e.g. "alice,bob=carol"
+//	leaderboard [-format text]   show the current standings as text, markdown
+//	            [-since date]    or html, with changes since the last match or
+//	            [-o card.png]    a date, or draw it to a PNG or SVG image
+//	stats <name>                 show a player's stats
+//	graph [-o elo.png]           draw the ELO graph as PNG or SVG
+//	      [-player name]         or one player's history, optionally against
+//	      [-median]              the league median
+//	      [-players a,b|-top n]  draw only some players
+//	      [-from date] [-to date]
+//	      [-season name]         draw only some matches
+//	      [-dates]               plot against match dates
+//	      [-labels n]            label ratings every n races, 0 for none
+//	recalc                       re-rate every player from the match history
+//
+// The league file is the league in multielo's JSON schema, log included, so
+// every change is kept along with who made it.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// defaultFile is the league file used when -f isn't given.
+const defaultFile = "league.json"
+
+var errUsage = errors.New("usage: multielo [-f league.json] init|player|match|leaderboard|stats|graph|recalc")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "multielo:", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command given by args, writing its output to stdout.
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("multielo", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	path := flags.String("f", defaultFile, "league file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	c := &cli{path: *path, stdout: stdout}
+	switch args[0] {
+	case "init":
+		return c.init(args[1:])
+	case "player":
+		return c.player(args[1:])
+	case "match":
+		return c.match(args[1:])
+	case "leaderboard":
+		return c.leaderboard(args[1:])
+	case "stats":
+		return c.stats(args[1:])
+	case "graph":
+		return c.graph(args[1:])
+	case "recalc":
+		return c.recalc(args[1:])
+	}
+
+	return fmt.Errorf("unknown command %q\n%w", args[0], errUsage)
+}
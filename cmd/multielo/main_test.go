@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+// cmd runs the command against the league file at path and returns its
+// output.
+func cmd(t *testing.T, path string, args ...string) (string, error) {
+	t.Helper()
+
+	var out bytes.Buffer
+	err := run(append([]string{"-f", path}, args...), &out)
+	return out.String(), err
+}
+
+func TestRun(t *testing.T) {
+	t.Run("League", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "league.json")
+
+		out, err := cmd(t, path, "init")
+		assert.NoError(t, err)
+		assert.Equal(t, "created "+path+"\n", out)
+
+		_, err = cmd(t, path, "init")
+		assert.Error(t, err)
+
+		for _, name := range []string{"alice", "bob", "carol"} {
+			_, err = cmd(t, path, "player", "add", name)
+			assert.NoError(t, err)
+		}
+		_, err = cmd(t, path, "player", "add", "-elo", "1200", "dave")
+		assert.NoError(t, err)
+		_, err = cmd(t, path, "player", "add", "-elo", "0", "erin")
+		assert.ErrorIs(t, err, multielo.ErrInvalidPlayer)
+
+		out, err = cmd(t, path, "match", "add", "alice,bob,carol")
+		assert.NoError(t, err)
+		assert.Contains(t, out, "alice")
+		assert.Contains(t, out, "+")
+
+		_, err = cmd(t, path, "match", "add", "dave,alice=carol")
+		assert.NoError(t, err)
+
+		_, err = cmd(t, path, "player", "rm", "bob")
+		assert.NoError(t, err)
+
+		out, err = cmd(t, path, "player", "ls")
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		assert.Len(t, lines, 5)
+		assert.Regexp(t, `^bob\s+\d+\s+1\s+retired$`, lines[2])
+
+		out, err = cmd(t, path, "leaderboard")
+		assert.NoError(t, err)
+		lines = strings.Split(strings.TrimSpace(out), "\n")
+		assert.Len(t, lines, 4)
+		assert.Regexp(t, `^\s*1\s+dave\s+`, lines[1])
+
+		out, err = cmd(t, path, "stats", "alice")
+		assert.NoError(t, err)
+		assert.Regexp(t, `played\s+2\n`, out)
+		assert.Regexp(t, `average place\s+1.50\n`, out)
+
+		_, err = cmd(t, path, "recalc")
+		assert.NoError(t, err)
+
+		out, err = cmd(t, path, "leaderboard", "-format", "markdown")
+		assert.NoError(t, err)
+		assert.Contains(t, out, "| 1 | dave |")
+
+		_, err = cmd(t, path, "leaderboard", "-since", "yesterday")
+		assert.Error(t, err)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "league.json")
+
+		_, err := cmd(t, path, "init")
+		assert.NoError(t, err)
+		_, err = cmd(t, path, "player", "add", "alice")
+		assert.NoError(t, err)
+		_, err = cmd(t, path, "player", "add", "bob")
+		assert.NoError(t, err)
+		_, err = cmd(t, path, "match", "add", "alice,bob")
+		assert.NoError(t, err)
+
+		// the file is a league in the versioned schema, log included
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		var version struct {
+			Version int `json:"version"`
+		}
+		assert.NoError(t, json.Unmarshal(data, &version))
+		assert.Equal(t, multielo.SchemaVersion, version.Version)
+
+		l := &multielo.League{}
+		assert.NoError(t, json.Unmarshal(data, l))
+		assert.Len(t, l.Matches, 1)
+		assert.Len(t, l.Log(), 4)
+
+		out, err := cmd(t, path, "stats", "alice")
+		assert.NoError(t, err)
+		assert.Regexp(t, `won\s+1\n`, out)
+	})
+
+	t.Run("Graph", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "league.json")
+
+		_, err := cmd(t, path, "init")
+		assert.NoError(t, err)
+		_, err = cmd(t, path, "player", "add", "alice")
+		assert.NoError(t, err)
+		_, err = cmd(t, path, "player", "add", "bob")
+		assert.NoError(t, err)
+		_, err = cmd(t, path, "match", "add", "alice,bob")
+		assert.NoError(t, err)
+
+		for _, name := range []string{"out.png", "out.svg"} {
+			out := filepath.Join(dir, name)
+			_, err = cmd(t, path, "graph", "-o", out)
+			assert.NoError(t, err)
+
+			info, err := os.Stat(out)
+			assert.NoError(t, err)
+			assert.NotZero(t, info.Size())
+		}
+
+		_, err = cmd(t, path, "graph", "-o", filepath.Join(dir, "out.gif"))
+		assert.Error(t, err)
+
+		player := filepath.Join(dir, "alice.svg")
+		_, err = cmd(t, path, "graph", "-o", player, "-player", "alice", "-median")
+		assert.NoError(t, err)
+		data, err := os.ReadFile(player)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "League median")
+
+		_, err = cmd(t, path, "graph", "-o", player, "-player", "mallory")
+		assert.ErrorIs(t, err, multielo.ErrPlayerNotFound)
+
+		filtered := filepath.Join(dir, "filtered.svg")
+		_, err = cmd(t, path, "graph", "-o", filtered, "-players", "bob", "-dates", "-labels", "0", "-from", "2000-01-01")
+		assert.NoError(t, err)
+		data, err = os.ReadFile(filtered)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "bob (")
+		assert.NotContains(t, string(data), "alice (")
+
+		_, err = cmd(t, path, "graph", "-o", filtered, "-to", "tomorrow")
+		assert.Error(t, err)
+
+		card := filepath.Join(dir, "leaderboard.png")
+		out, err := cmd(t, path, "leaderboard", "-o", card)
+		assert.NoError(t, err)
+		assert.Equal(t, "wrote "+card+"\n", out)
+		data, err = os.ReadFile(card)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(data), "\x89PNG"))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "league.json")
+
+		_, err := cmd(t, path, "leaderboard")
+		assert.ErrorContains(t, err, "multielo init")
+
+		_, err = cmd(t, path, "init")
+		assert.NoError(t, err)
+		_, err = cmd(t, path, "player", "add", "alice")
+		assert.NoError(t, err)
+		before, err := os.ReadFile(path)
+		assert.NoError(t, err)
+
+		_, err = cmd(t, path, "player", "add", "alice")
+		assert.Error(t, err)
+		_, err = cmd(t, path, "match", "add", "alice,mallory")
+		assert.Error(t, err)
+		_, err = cmd(t, path, "stats", "mallory")
+		assert.Error(t, err)
+		_, err = cmd(t, path, "dance")
+		assert.Error(t, err)
+		_, err = cmd(t, path)
+		assert.ErrorIs(t, err, errUsage)
+
+		// failed commands leave the file alone
+		after, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+	})
+}
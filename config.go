@@ -0,0 +1,283 @@
+package multielo
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultKFactor is the total K value at stake for each player in a
+	// match, shared between their pairings with every opponent.
+	DefaultKFactor = 32
+	// DefaultFormWindow is how many recent finishes are kept in
+	// PlayerStats.Last5Finish.
+	DefaultFormWindow = 5
+)
+
+// Config holds every tunable setting of a league. It is stored with the
+// league so that replaying its matches always gives the same ratings, and can
+// be loaded from JSON or YAML with LoadConfig. Zero values fall back to the
+// package defaults.
+type Config struct {
+	// KFactor is the K value each player has at stake in a match. Each
+	// pairing in a match of n players is scored with KFactor/(n-1).
+	KFactor int `json:"k_factor,omitempty" yaml:"k_factor,omitempty"`
+	// InitialELO is the rating new players start on.
+	InitialELO int `json:"initial_elo,omitempty" yaml:"initial_elo,omitempty"`
+	// FormWindow is how many recent finishes are kept for each player.
+	FormWindow int `json:"form_window,omitempty" yaml:"form_window,omitempty"`
+	// Colors is the palette GenerateGraph draws players in, as hex RGB
+	// strings such as "#ff0000".
+	Colors []string `json:"colors,omitempty" yaml:"colors,omitempty"`
+	// PlayerColors pins players' colours in graphs, by name, as hex RGB
+	// strings. Other players are drawn in the rest of the palette.
+	PlayerColors map[string]string `json:"player_colors,omitempty" yaml:"player_colors,omitempty"`
+
+	// Decay is the league's inactivity policy. It is off when nil.
+	Decay *DecayPolicy `json:"decay,omitempty" yaml:"decay,omitempty"`
+	// Provisional is the league's policy for newcomers' ratings. It is off
+	// when nil.
+	Provisional *ProvisionalPolicy `json:"provisional,omitempty" yaml:"provisional,omitempty"`
+}
+
+// Option configures a league created with NewLeague.
+type Option func(*Config)
+
+// DefaultConfig returns the settings a league uses when none are given.
+func DefaultConfig() Config {
+	return Config{
+		KFactor:    DefaultKFactor,
+		InitialELO: InitialELO,
+		FormWindow: DefaultFormWindow,
+	}
+}
+
+// WithConfig replaces the league's whole configuration.
+func WithConfig(c Config) Option {
+	return func(config *Config) {
+		*config = c
+	}
+}
+
+// WithKFactor sets the K value each player has at stake in a match.
+func WithKFactor(k int) Option {
+	return func(config *Config) {
+		config.KFactor = k
+	}
+}
+
+// WithInitialELO sets the rating new players start on.
+func WithInitialELO(elo int) Option {
+	return func(config *Config) {
+		config.InitialELO = elo
+	}
+}
+
+// WithFormWindow sets how many recent finishes are kept for each player.
+func WithFormWindow(n int) Option {
+	return func(config *Config) {
+		config.FormWindow = n
+	}
+}
+
+// WithColors sets the palette GenerateGraph draws players in, as hex RGB
+// strings.
+func WithColors(colors ...string) Option {
+	return func(config *Config) {
+		config.Colors = colors
+	}
+}
+
+// WithPlayerColor pins the colour a player is drawn in, as a hex RGB string.
+func WithPlayerColor(name, hex string) Option {
+	return func(config *Config) {
+		colors := map[string]string{}
+		for player, color := range config.PlayerColors {
+			colors[player] = color
+		}
+		colors[name] = hex
+		config.PlayerColors = colors
+	}
+}
+
+// WithDecay turns on an inactivity policy.
+func WithDecay(policy DecayPolicy) Option {
+	return func(config *Config) {
+		config.Decay = &policy
+	}
+}
+
+// WithProvisional turns on provisional ratings for newcomers.
+func WithProvisional(policy ProvisionalPolicy) Option {
+	return func(config *Config) {
+		config.Provisional = &policy
+	}
+}
+
+// LoadConfig reads a configuration from a JSON or YAML file, chosen by its
+// extension. Settings missing from the file keep their default values.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	config := DefaultConfig()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &config)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &config)
+	default:
+		return Config{}, fmt.Errorf("unknown config format %q: %w", filepath.Ext(path), ErrInvalidConfig)
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	return config, config.Validate()
+}
+
+// Validate reports whether every setting is usable.
+func (c Config) Validate() error {
+	if c.KFactor < 0 || c.InitialELO < 0 || c.FormWindow < 0 {
+		return ErrInvalidConfig
+	}
+
+	if _, err := c.palette(); err != nil {
+		return err
+	}
+
+	for name, hex := range c.PlayerColors {
+		if _, err := parseColor(hex); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	if c.Decay != nil && (c.Decay.IdlePeriod < 0 || c.Decay.Points < 0 || c.Decay.Interval < 0) {
+		return fmt.Errorf("decay: %w", ErrInvalidConfig)
+	}
+
+	if c.Provisional != nil && (c.Provisional.Matches < 0 || c.Provisional.KMultiplier < 0 || c.Provisional.OpponentWeight < 0) {
+		return fmt.Errorf("provisional: %w", ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+func (c Config) kFactor() int {
+	if c.KFactor == 0 {
+		return DefaultKFactor
+	}
+
+	return c.KFactor
+}
+
+func (c Config) initialELO() int {
+	if c.InitialELO == 0 {
+		return InitialELO
+	}
+
+	return c.InitialELO
+}
+
+func (c Config) formWindow() int {
+	if c.FormWindow == 0 {
+		return DefaultFormWindow
+	}
+
+	return c.FormWindow
+}
+
+// palette returns the colours players are drawn in.
+func (c Config) palette() ([]color.Color, error) {
+	if len(c.Colors) == 0 {
+		return colors, nil
+	}
+
+	palette := make([]color.Color, 0, len(c.Colors))
+	for _, hex := range c.Colors {
+		color, err := parseColor(hex)
+		if err != nil {
+			return nil, err
+		}
+
+		palette = append(palette, color)
+	}
+
+	return palette, nil
+}
+
+// parseColor parses a hex RGB colour such as "#ff0000".
+func parseColor(hex string) (color.Color, error) {
+	rgb, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(hex, "#")) != 6 {
+		return nil, fmt.Errorf("colour %q: %w", hex, ErrInvalidConfig)
+	}
+
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 255}, nil
+}
+
+// decayPolicyJSON is how a DecayPolicy is written to JSON, with durations as
+// strings such as "336h" rather than nanoseconds.
+type decayPolicyJSON struct {
+	IdlePeriod string `json:"idle_period"`
+	Points     int    `json:"points"`
+	Interval   string `json:"interval"`
+	Floor      int    `json:"floor"`
+	HideIdle   bool   `json:"hide_idle"`
+}
+
+func (d DecayPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(decayPolicyJSON{
+		IdlePeriod: d.IdlePeriod.String(),
+		Points:     d.Points,
+		Interval:   d.Interval.String(),
+		Floor:      d.Floor,
+		HideIdle:   d.HideIdle,
+	})
+}
+
+func (d *DecayPolicy) UnmarshalJSON(data []byte) error {
+	var policy decayPolicyJSON
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return err
+	}
+
+	idlePeriod, err := parseDuration(policy.IdlePeriod)
+	if err != nil {
+		return err
+	}
+
+	interval, err := parseDuration(policy.Interval)
+	if err != nil {
+		return err
+	}
+
+	*d = DecayPolicy{
+		IdlePeriod: idlePeriod,
+		Points:     policy.Points,
+		Interval:   interval,
+		Floor:      policy.Floor,
+		HideIdle:   policy.HideIdle,
+	}
+
+	return nil
+}
+
+// parseDuration is time.ParseDuration, except an empty string is zero.
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	return time.ParseDuration(s)
+}
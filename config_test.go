@@ -0,0 +1,146 @@
+package multielo_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeague_NewLeagueOptions(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		l := multielo.NewLeague()
+		assert.Equal(t, multielo.DefaultConfig(), l.Config)
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		l := multielo.NewLeague(
+			multielo.WithKFactor(64),
+			multielo.WithInitialELO(1200),
+			multielo.WithFormWindow(3),
+			multielo.WithProvisional(multielo.ProvisionalPolicy{Matches: 1, KMultiplier: 1, OpponentWeight: 1}),
+		)
+
+		assert.Equal(t, 64, l.Config.KFactor)
+		assert.NotNil(t, l.Config.Provisional)
+		assert.Nil(t, l.Config.Decay)
+
+		assert.NoError(t, l.AddPlayer("alice"))
+		assert.NoError(t, l.AddPlayer("bob"))
+
+		diff := recordMatch(t, l, "alice", "bob")
+		assert.Equal(t, 32, diff[0].Diff)
+		assert.Equal(t, 1232, l.Players[0].ELO)
+
+		for i := 0; i < 4; i++ {
+			recordMatch(t, l, "bob", "alice")
+		}
+		assert.Equal(t, []int{2, 2, 2}, l.Players[0].Stats.Last5Finish)
+	})
+
+	t.Run("WithConfig", func(t *testing.T) {
+		config := multielo.Config{KFactor: 16}
+		l := multielo.NewLeague(multielo.WithConfig(config), multielo.WithFormWindow(10))
+		assert.Equal(t, 16, l.Config.KFactor)
+		assert.Equal(t, 10, l.Config.FormWindow)
+	})
+
+	t.Run("WithColors", func(t *testing.T) {
+		l := multielo.NewLeague(multielo.WithColors("#112233", "445566"))
+		assert.NoError(t, l.Config.Validate())
+		assert.NoError(t, l.AddPlayer("alice"))
+
+		_, err := l.GenerateGraph()
+		assert.NoError(t, err)
+
+		l.Config.Colors = []string{"not a colour"}
+		assert.ErrorIs(t, l.Config.Validate(), multielo.ErrInvalidConfig)
+		_, err = l.GenerateGraph()
+		assert.ErrorIs(t, err, multielo.ErrInvalidConfig)
+	})
+
+	t.Run("WithPlayerColor", func(t *testing.T) {
+		l := multielo.NewLeague(multielo.WithPlayerColor("alice", "#112233"), multielo.WithPlayerColor("bob", "#445566"))
+		assert.Equal(t, map[string]string{"alice": "#112233", "bob": "#445566"}, l.Config.PlayerColors)
+		assert.NoError(t, l.Config.Validate())
+
+		l.Config.PlayerColors["alice"] = "red"
+		assert.ErrorIs(t, l.Config.Validate(), multielo.ErrInvalidConfig)
+	})
+}
+
+func TestConfig_LoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("JSON", func(t *testing.T) {
+		path := filepath.Join(dir, "league.json")
+		data := `{
+			"k_factor": 24,
+			"decay": {"idle_period": "336h", "points": 10, "interval": "168h", "floor": 900}
+		}`
+		assert.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+		config, err := multielo.LoadConfig(path)
+		assert.NoError(t, err)
+		assert.Equal(t, 24, config.KFactor)
+		assert.Equal(t, multielo.InitialELO, config.InitialELO)
+		assert.Equal(t, multielo.DefaultFormWindow, config.FormWindow)
+		assert.Equal(t, 14*24*time.Hour, config.Decay.IdlePeriod)
+		assert.Equal(t, 7*24*time.Hour, config.Decay.Interval)
+		assert.Equal(t, 900, config.Decay.Floor)
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		path := filepath.Join(dir, "league.yaml")
+		data := "initial_elo: 1500\n" +
+			"colors: ['#ff0000', '#00ff00']\n" +
+			"player_colors: {alice: '#0000ff'}\n" +
+			"decay:\n  idle_period: 336h\n  points: 5\n  interval: 24h\n" +
+			"provisional:\n  matches: 5\n  k_multiplier: 2\n  opponent_weight: 0.5\n"
+		assert.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+		config, err := multielo.LoadConfig(path)
+		assert.NoError(t, err)
+		assert.Equal(t, 1500, config.InitialELO)
+		assert.Equal(t, multielo.DefaultKFactor, config.KFactor)
+		assert.Equal(t, []string{"#ff0000", "#00ff00"}, config.Colors)
+		assert.Equal(t, map[string]string{"alice": "#0000ff"}, config.PlayerColors)
+		assert.Equal(t, 24*time.Hour, config.Decay.Interval)
+		assert.Equal(t, 0.5, config.Provisional.OpponentWeight)
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		config := multielo.DefaultConfig()
+		config.Decay = &multielo.DecayPolicy{IdlePeriod: time.Hour, Points: 1, Interval: time.Minute}
+
+		data, err := json.Marshal(config)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"idle_period":"1h0m0s"`)
+
+		path := filepath.Join(dir, "roundtrip.json")
+		assert.NoError(t, os.WriteFile(path, data, 0o644))
+
+		loaded, err := multielo.LoadConfig(path)
+		assert.NoError(t, err)
+		assert.Equal(t, config, loaded)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		path := filepath.Join(dir, "league.toml")
+		assert.NoError(t, os.WriteFile(path, []byte(""), 0o644))
+		_, err := multielo.LoadConfig(path)
+		assert.ErrorIs(t, err, multielo.ErrInvalidConfig)
+
+		path = filepath.Join(dir, "negative.json")
+		assert.NoError(t, os.WriteFile(path, []byte(`{"k_factor": -1}`), 0o644))
+		_, err = multielo.LoadConfig(path)
+		assert.ErrorIs(t, err, multielo.ErrInvalidConfig)
+
+		_, err = multielo.LoadConfig(filepath.Join(dir, "missing.json"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -0,0 +1,298 @@
+package multielo
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvDateFormats are the date formats ImportCSV understands, tried in order.
+var csvDateFormats = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// csvColumns maps the header names ImportCSV accepts to the columns they
+// name.
+var csvColumns = map[string]string{
+	"date":     "date",
+	"match":    "match",
+	"match_id": "match",
+	"id":       "match",
+	"player":   "player",
+	"name":     "player",
+	"position": "position",
+	"place":    "position",
+}
+
+// csvMatch is a match read from a CSV file.
+type csvMatch struct {
+	line    int
+	date    time.Time
+	results []*MatchResult
+}
+
+// ImportCSV records the matches in a CSV file, one row per player per match,
+// and returns how many were recorded.
+//
+// Each row holds a date, an optional match id, a player and their position.
+// Rows with the same date and match id are one match, so the id only needs
+// to be set to tell apart matches played at the same time. In particular,
+// rows with a date but no time or match id are all one match for that day.
+// The columns are date, match, player and position in that order, or date,
+// player and position if there are only three, unless the first row is a
+// header naming them. Dates are RFC 3339 or "2006-01-02", optionally
+// followed by a time.
+//
+// Matches are recorded in date order and must all come after the league's
+// current matches. Players not yet in the league are added. The whole import
+// is tried on a copy of the league first, so if any of it fails nothing is
+// recorded.
+func (l *League) ImportCSV(r io.Reader) (int, error) {
+	matches, err := readCSVMatches(r)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(matches) == 0 {
+		return 0, nil
+	}
+
+	scratch, err := l.clone()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := scratch.importMatches(matches); err != nil {
+		return 0, err
+	}
+
+	if err := l.importMatches(matches); err != nil {
+		return 0, err
+	}
+
+	return len(matches), nil
+}
+
+// importMatches adds any new players and records each match in turn.
+func (l *League) importMatches(matches []*csvMatch) error {
+	if len(l.Matches) > 0 && matches[0].date.Before(l.Matches[len(l.Matches)-1].Date) {
+		return fmt.Errorf("line %d: match on %s is before the league's last match: %w", matches[0].line, matches[0].date.Format(time.DateOnly), ErrInvalidMatch)
+	}
+
+	for _, match := range matches {
+		for _, result := range match.results {
+			p, err := l.GetPlayer(result.Player.Name)
+			if errors.Is(err, ErrPlayerNotFound) {
+				continue
+			}
+
+			if p.Retired {
+				return fmt.Errorf("line %d: player %q is retired: %w", match.line, p.Name, ErrPlayerRetired)
+			}
+		}
+	}
+
+	for _, match := range matches {
+		for _, result := range match.results {
+			if _, err := l.GetPlayer(result.Player.Name); errors.Is(err, ErrPlayerNotFound) {
+				if err := l.AddPlayer(result.Player.Name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, match := range matches {
+		if _, err := l.addMatch(match.results, match.date); err != nil {
+			return fmt.Errorf("line %d: %w", match.line, err)
+		}
+	}
+
+	return nil
+}
+
+// readCSVMatches reads and checks every match in a CSV file, sorted by date.
+func readCSVMatches(r io.Reader) ([]*csvMatch, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMatch, err)
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	// work out which column is which
+	columns := map[string]int{"date": 0, "match": 1, "player": 2, "position": 3}
+	if len(records[0]) == 3 {
+		columns = map[string]int{"date": 0, "match": -1, "player": 1, "position": 2}
+	}
+
+	first := 0
+	if _, err := parseCSVDate(records[0][0]); err != nil {
+		columns = map[string]int{"match": -1}
+		for i, name := range records[0] {
+			if column, ok := csvColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+				columns[column] = i
+			}
+		}
+
+		for _, column := range []string{"date", "player", "position"} {
+			if _, ok := columns[column]; !ok {
+				return nil, fmt.Errorf("line 1: no %s column: %w", column, ErrInvalidMatch)
+			}
+		}
+		first = 1
+	}
+
+	field := func(record []string, column string) string {
+		if i := columns[column]; i >= 0 && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	// group the rows into matches, in the order they first appear
+	byKey := map[string]*csvMatch{}
+	matches := []*csvMatch{}
+	for i, record := range records[first:] {
+		line := first + i + 1
+
+		date, err := parseCSVDate(field(record, "date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		name := strings.ToLower(field(record, "player"))
+		if name == "" {
+			return nil, fmt.Errorf("line %d: no player: %w", line, ErrInvalidMatch)
+		}
+
+		position, err := strconv.Atoi(field(record, "position"))
+		if err != nil || position < 1 {
+			return nil, fmt.Errorf("line %d: invalid position %q: %w", line, field(record, "position"), ErrInvalidMatch)
+		}
+
+		key := date.Format(time.RFC3339Nano) + "\x00" + field(record, "match")
+		match, ok := byKey[key]
+		if !ok {
+			match = &csvMatch{line: line, date: date}
+			byKey[key] = match
+			matches = append(matches, match)
+		}
+
+		for _, result := range match.results {
+			if result.Player.Name == name {
+				return nil, fmt.Errorf("line %d: player %q appears twice in a match: %w", line, name, ErrInvalidMatch)
+			}
+		}
+
+		match.results = append(match.results, &MatchResult{Player: &Player{Name: name}, Position: position})
+	}
+
+	for _, match := range matches {
+		if len(match.results) < 2 {
+			return nil, fmt.Errorf("line %d: match has fewer than two players: %w", match.line, ErrInvalidMatch)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].date.Before(matches[j].date)
+	})
+
+	return matches, nil
+}
+
+func parseCSVDate(s string) (time.Time, error) {
+	for _, format := range csvDateFormats {
+		if date, err := time.Parse(format, strings.TrimSpace(s)); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q: %w", s, ErrInvalidMatch)
+}
+
+// ExportPlayersCSV writes every player and their stats as CSV, with a header
+// row.
+func (l *League) ExportPlayersCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{
+		"player", "elo", "starting_elo", "peak_elo", "provisional", "retired",
+		"matches_played", "matches_won", "average_place", "recent_finishes",
+		"form_score", "weighted_average_finish",
+		"win_streak", "longest_win_streak", "podium_streak", "longest_podium_streak",
+	})
+
+	for _, p := range l.Players {
+		finishes := make([]string, 0, len(p.Stats.Last5Finish))
+		for _, position := range p.Stats.Last5Finish {
+			finishes = append(finishes, strconv.Itoa(position))
+		}
+
+		writer.Write([]string{
+			p.Name,
+			strconv.Itoa(p.ELO),
+			strconv.Itoa(p.StartingELO),
+			strconv.Itoa(p.Stats.PeakELO),
+			strconv.FormatBool(l.IsProvisional(p)),
+			strconv.FormatBool(p.Retired),
+			strconv.Itoa(p.Stats.MatchesPlayed),
+			strconv.Itoa(p.Stats.MatchesWon),
+			strconv.FormatFloat(p.Stats.AveragePlace(), 'f', -1, 64),
+			strings.Join(finishes, " "),
+			strconv.FormatFloat(p.Stats.FormScore, 'f', -1, 64),
+			strconv.FormatFloat(p.Stats.WeightedAverageFinish, 'f', -1, 64),
+			strconv.Itoa(p.Stats.WinStreak),
+			strconv.Itoa(p.Stats.LongestWinStreak),
+			strconv.Itoa(p.Stats.PodiumStreak),
+			strconv.Itoa(p.Stats.LongestPodiumStreak),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportMatchesCSV writes every result of the current season's matches as
+// CSV, one row per player per match, with a header row. The first four
+// columns are the ones ImportCSV reads, so the file can be imported into
+// another league.
+func (l *League) ExportMatchesCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"date", "match", "player", "position", "elo_before", "elo_change", "elo_after", "provisional"})
+
+	for _, match := range l.Matches {
+		for _, result := range match.Results {
+			if result.Player == nil {
+				continue
+			}
+
+			writer.Write([]string{
+				match.Date.Format(time.RFC3339),
+				strconv.Itoa(match.ID),
+				result.Player.Name,
+				strconv.Itoa(result.Position),
+				strconv.Itoa(result.ELOBefore),
+				strconv.Itoa(result.ELOChange),
+				strconv.Itoa(result.ELOBefore + result.ELOChange),
+				strconv.FormatBool(result.Provisional),
+			})
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,168 @@
+package multielo_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeague_ImportCSV(t *testing.T) {
+	t.Run("ImportCSV", func(t *testing.T) {
+		l := newTestLeague(t, "alice")
+
+		n, err := l.ImportCSV(strings.NewReader(`date,match,player,position
+2024-01-02,,alice,2
+2024-01-02,,bob,1
+2024-01-01,1,Alice,1
+2024-01-01,1,bob,2
+2024-01-01,2,carol,1
+2024-01-01,2,alice,2
+2024-01-01,2,bob,3
+`))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		assert.Len(t, l.Players, 3)
+		assert.Len(t, l.Matches, 3)
+
+		// sorted by date, keeping the file's order within a day
+		day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+		assert.Equal(t, day(1), l.Matches[0].Date)
+		assert.Equal(t, "alice", l.Matches[0].Results[0].Player.Name)
+		assert.Equal(t, day(1), l.Matches[1].Date)
+		assert.Equal(t, "carol", l.Matches[1].Results[0].Player.Name)
+		assert.Equal(t, day(2), l.Matches[2].Date)
+		assert.Equal(t, 1, l.Matches[2].Results[1].Position)
+
+		alice, err := l.GetPlayer("alice")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, alice.Stats.MatchesPlayed)
+		assert.Equal(t, day(2), l.Log()[len(l.Log())-1].Time)
+	})
+
+	t.Run("Columns", func(t *testing.T) {
+		l := multielo.NewLeague()
+
+		// no header, three columns
+		n, err := l.ImportCSV(strings.NewReader("2024-01-01 18:30,alice,1\n2024-01-01 18:30,bob,2\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, time.Date(2024, 1, 1, 18, 30, 0, 0, time.UTC), l.Matches[0].Date)
+
+		// a header in any order
+		n, err = l.ImportCSV(strings.NewReader("Name,Place,Date\nbob,1,2024-02-01\nalice,2,2024-02-01\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, "bob", l.Matches[1].Results[0].Player.Name)
+
+		n, err = l.ImportCSV(strings.NewReader(""))
+		assert.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("SameDay", func(t *testing.T) {
+		l := multielo.NewLeague()
+
+		// rows with a date but no time or match id are one match for the day
+		n, err := l.ImportCSV(strings.NewReader("2024-01-01,,alice,1\n2024-01-01,,bob,2\n2024-01-01,,carol,1\n2024-01-01,,dave,2\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Len(t, l.Matches[0].Results, 4)
+
+		// so the same player twice that day is an error, not two matches
+		_, err = l.ImportCSV(strings.NewReader("2024-01-02,,alice,1\n2024-01-02,,bob,2\n2024-01-02,,alice,2\n2024-01-02,,bob,1\n"))
+		assert.ErrorIs(t, err, multielo.ErrInvalidMatch)
+		assert.Len(t, l.Matches, 1)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		assert.NoError(t, l.RemovePlayer("bob"))
+
+		for name, data := range map[string]string{
+			"BadDate":     "yesterday,,alice,1\nyesterday,,carol,2\n",
+			"BadPosition": "2024-01-01,,alice,first\n2024-01-01,,carol,2\n",
+			"NoPlayer":    "2024-01-01,,,1\n2024-01-01,,carol,2\n",
+			"OnePlayer":   "2024-01-01,,alice,1\n2024-01-02,,carol,1\n2024-01-02,,dave,2\n",
+			"Duplicate":   "2024-01-01,,alice,1\n2024-01-01,,alice,2\n",
+			"NoColumn":    "date,player\n2024-01-01,alice\n",
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := l.ImportCSV(strings.NewReader(data))
+				assert.ErrorIs(t, err, multielo.ErrInvalidMatch)
+			})
+		}
+
+		_, err := l.ImportCSV(strings.NewReader("2024-01-01,,alice,1\n2024-01-01,,carol,2\n2024-01-02,,alice,1\n2024-01-02,,bob,2\n"))
+		assert.ErrorIs(t, err, multielo.ErrPlayerRetired)
+
+		// nothing is recorded from a bad file
+		assert.Empty(t, l.Matches)
+		assert.Len(t, l.Players, 2)
+
+		assert.NoError(t, l.RestorePlayer("bob"))
+		recordMatch(t, l, "alice", "bob")
+		_, err = l.ImportCSV(strings.NewReader("2024-01-01,,alice,1\n2024-01-01,,bob,2\n"))
+		assert.ErrorIs(t, err, multielo.ErrInvalidMatch)
+	})
+}
+
+func TestLeague_ExportCSV(t *testing.T) {
+	t.Run("ExportMatchesCSV", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol")
+		recordMatch(t, l, "alice", "bob", "carol")
+		recordMatch(t, l, "carol", "bob")
+
+		var buf bytes.Buffer
+		assert.NoError(t, l.ExportMatchesCSV(&buf))
+
+		records, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 6)
+		assert.Equal(t, []string{"date", "match", "player", "position", "elo_before", "elo_change", "elo_after", "provisional"}, records[0])
+		assert.Equal(t, []string{"1", "alice", "1", "1000", "16", "1016", "false"}, records[1][1:])
+		assert.Equal(t, "2", records[4][1])
+
+		// exported matches import into another league with the same ratings
+		other := multielo.NewLeague()
+		n, err := other.ImportCSV(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		for _, p := range l.Players {
+			imported, err := other.GetPlayer(p.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, p.ELO, imported.ELO)
+		}
+	})
+
+	t.Run("ExportPlayersCSV", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		recordMatch(t, l, "alice", "bob")
+		recordMatch(t, l, "bob", "alice")
+		assert.NoError(t, l.RemovePlayer("bob"))
+
+		var buf bytes.Buffer
+		assert.NoError(t, l.ExportPlayersCSV(&buf))
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 3)
+
+		row := map[string]string{}
+		for i, column := range records[0] {
+			row[column] = records[1][i]
+		}
+		assert.Equal(t, "alice", row["player"])
+		assert.Equal(t, "2", row["matches_played"])
+		assert.Equal(t, "1.5", row["average_place"])
+		assert.Equal(t, "1 2", row["recent_finishes"])
+		assert.Equal(t, "1016", row["peak_elo"])
+
+		assert.Equal(t, "bob", records[2][0])
+		assert.Contains(t, records[2], "true")
+	})
+}
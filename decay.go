@@ -0,0 +1,122 @@
+package multielo
+
+import (
+	"time"
+)
+
+// DecayPolicy stops players who have stopped playing from holding their
+// place on the leaderboard forever.
+//
+// Decay is measured from the date of a player's last match in the current
+// season and is always worked out afresh from the rating they finished that
+// match on, so applying it more than once never compounds. Players who
+// haven't played yet this season are left alone.
+type DecayPolicy struct {
+	// IdlePeriod is how long a player can go without a match before the
+	// policy takes effect.
+	IdlePeriod time.Duration `yaml:"idle_period"`
+
+	// Points is how much rating an idle player loses for every full
+	// Interval past the idle period. Ratings never decay below Floor, and a
+	// player already below it is left where they are. Points of zero leaves
+	// ratings alone.
+	Points   int           `yaml:"points"`
+	Interval time.Duration `yaml:"interval"`
+	Floor    int           `yaml:"floor"`
+
+	// HideIdle leaves idle players off the leaderboard until they play
+	// again.
+	HideIdle bool `yaml:"hide_idle"`
+}
+
+// ApplyDecay brings every active player's rating up to date with the
+// league's decay policy as of now, and returns the players whose rating
+// changed along with how much by. It does nothing if the league has no
+// policy.
+func (l *League) ApplyDecay(now time.Time) []MatchDiff {
+	matchDiff := []MatchDiff{}
+	if l.Config.Decay == nil {
+		return matchDiff
+	}
+
+	for _, p := range l.Players {
+		if p.Retired {
+			continue
+		}
+
+		elo := l.ratingAt(p, now)
+		if elo != p.ELO {
+			matchDiff = append(matchDiff, MatchDiff{
+				Player: p,
+				Diff:   elo - p.ELO,
+			})
+			p.ELO = elo
+			l.emitRatingChanged(p, matchDiff[len(matchDiff)-1].Diff)
+		}
+	}
+
+	return matchDiff
+}
+
+// IsIdle reports whether a player has gone longer than the decay policy's
+// idle period without a match. It is always false if the league has no
+// policy.
+func (l *League) IsIdle(p *Player, now time.Time) bool {
+	if l.Config.Decay == nil {
+		return false
+	}
+
+	_, date, ok := l.lastResult(p)
+	return ok && now.Sub(date) > l.Config.Decay.IdlePeriod
+}
+
+// hidden reports whether a player should be left off the leaderboard for
+// being idle.
+func (l *League) hidden(p *Player, now time.Time) bool {
+	return l.Config.Decay != nil && l.Config.Decay.HideIdle && l.IsIdle(p, now)
+}
+
+// ratingAt returns the rating a player should have at now once decay is
+// taken into account.
+func (l *League) ratingAt(p *Player, now time.Time) int {
+	if l.Config.Decay == nil {
+		return p.ELO
+	}
+
+	result, date, ok := l.lastResult(p)
+	if !ok {
+		return p.ELO
+	}
+
+	return l.Config.Decay.apply(result.ELOBefore+result.ELOChange, now.Sub(date))
+}
+
+// lastResult returns a player's most recent result this season and the date
+// of the match it came from.
+func (l *League) lastResult(p *Player) (*MatchResult, time.Time, bool) {
+	for i := len(l.Matches) - 1; i >= 0; i-- {
+		for _, result := range l.Matches[i].Results {
+			if result.Player != nil && result.Player.Name == p.Name {
+				return result, l.Matches[i].Date, true
+			}
+		}
+	}
+
+	return nil, time.Time{}, false
+}
+
+// apply returns the rating a player finishing a match on elo should have
+// after being idle for the given time. A nil policy never decays.
+func (d *DecayPolicy) apply(elo int, idle time.Duration) int {
+	if d == nil || d.Points <= 0 || d.Interval <= 0 || idle <= d.IdlePeriod || elo <= d.Floor {
+		return elo
+	}
+
+	periods := int((idle - d.IdlePeriod) / d.Interval)
+	elo -= periods * d.Points
+	if elo < d.Floor {
+		elo = d.Floor
+	}
+
+	return elo
+}
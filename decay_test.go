@@ -0,0 +1,112 @@
+package multielo_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+const day = 24 * time.Hour
+
+func newDecayLeague(t *testing.T) *multielo.League {
+	t.Helper()
+
+	l := newTestLeague(t, "alice", "bob", "carol")
+	l.Config.Decay = &multielo.DecayPolicy{
+		IdlePeriod: 14 * day,
+		Points:     10,
+		Interval:   7 * day,
+		Floor:      975,
+	}
+
+	return l
+}
+
+func TestLeague_ApplyDecay(t *testing.T) {
+	t.Run("ApplyDecay", func(t *testing.T) {
+		l := newDecayLeague(t)
+		recordMatch(t, l, "alice", "bob")
+
+		now := time.Now()
+		l.Matches[0].Date = now.Add(-30 * day)
+
+		diff := l.ApplyDecay(now)
+		assert.Len(t, diff, 2)
+
+		alice, _ := l.GetPlayer("alice")
+		bob, _ := l.GetPlayer("bob")
+		carol, _ := l.GetPlayer("carol")
+		assert.Equal(t, 1016-20, alice.ELO)
+		assert.Equal(t, 984-9, bob.ELO)
+		assert.Equal(t, multielo.InitialELO, carol.ELO)
+		assert.Equal(t, 1016, alice.Stats.PeakELO)
+
+		// applying it again at the same time changes nothing
+		assert.Empty(t, l.ApplyDecay(now))
+		assert.Equal(t, 1016-20, alice.ELO)
+
+		// decay stops at the floor
+		l.ApplyDecay(now.Add(100 * day))
+		assert.Equal(t, 975, alice.ELO)
+		assert.Equal(t, 975, bob.ELO)
+	})
+
+	t.Run("ApplyDecayNoPolicy", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		recordMatch(t, l, "alice", "bob")
+		l.Matches[0].Date = time.Now().Add(-365 * day)
+
+		assert.Empty(t, l.ApplyDecay(time.Now()))
+		assert.False(t, l.IsIdle(l.Players[0], time.Now()))
+	})
+
+	t.Run("DecayBeforeMatch", func(t *testing.T) {
+		l := newDecayLeague(t)
+		recordMatch(t, l, "alice", "bob")
+		l.Matches[0].Date = time.Now().Add(-30 * day)
+
+		recordMatch(t, l, "alice", "bob")
+		assert.Equal(t, 1016-20, l.Matches[1].Results[0].ELOBefore)
+		assert.Equal(t, 975, l.Matches[1].Results[1].ELOBefore)
+
+		alice, _ := l.GetPlayer("alice")
+		elo := alice.ELO
+		assert.NoError(t, l.Recalculate())
+		assert.Equal(t, elo, alice.ELO)
+	})
+
+	t.Run("PredictWithDecay", func(t *testing.T) {
+		l := newDecayLeague(t)
+		recordMatch(t, l, "alice", "bob")
+		l.Matches[0].Date = time.Now().Add(-30 * day)
+
+		// predictions use the ratings the match would be recorded with
+		predictions, err := l.Predict([]string{"alice", "bob"})
+		assert.NoError(t, err)
+		assert.InDelta(t, 1/(1+math.Pow(10, float64(975-(1016-20))/400)), predictions[0].ExpectedScore, 1e-9)
+
+		alice, _ := l.GetPlayer("alice")
+		assert.Equal(t, 1016, alice.ELO)
+	})
+}
+
+func TestLeague_LeaderboardHidesIdle(t *testing.T) {
+	l := newDecayLeague(t)
+	l.Config.Decay.HideIdle = true
+
+	recordMatch(t, l, "alice", "bob")
+	recordMatch(t, l, "carol", "bob")
+	l.Matches[0].Date = time.Now().Add(-30 * day)
+
+	alice, _ := l.GetPlayer("alice")
+	assert.True(t, l.IsIdle(alice, time.Now()))
+
+	leaderboard := l.Leaderboard()
+	assert.Len(t, leaderboard, 2)
+	for _, p := range leaderboard {
+		assert.NotEqual(t, "alice", p.Name)
+	}
+}
@@ -0,0 +1,248 @@
+// Package discord runs a League as a Discord bot, answering the slash
+// commands /race, /elo, /leaderboard and /graph.
+//
+// The bot talks to Discord through a Gateway, so it isn't tied to any one
+// Discord library. Wrap your library's session in a Gateway to run it for
+// real, or use a FakeGateway to test it offline.
+package discord
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/distrobyte/multielo"
+)
+
+// ErrUnknownCommand is returned for a slash command the bot doesn't know.
+var ErrUnknownCommand = errors.New("unknown command")
+
+// Interaction is a slash command run by a user.
+type Interaction struct {
+	ID        string
+	ChannelID string
+	// User is the name of whoever ran the command. It is recorded in the
+	// league's log against any change the command makes.
+	User    string
+	Command string
+	// Options holds the value given for each of the command's options, by
+	// option name.
+	Options map[string]string
+}
+
+// Response is the bot's reply to an interaction.
+type Response struct {
+	Content string
+	Embeds  []Embed
+	Files   []File
+	// Ephemeral replies are only shown to the user who ran the command.
+	Ephemeral bool
+}
+
+// Embed is a Discord rich embed.
+type Embed struct {
+	Title       string
+	Description string
+	Color       int
+	Fields      []EmbedField
+	Footer      string
+	// Image names a file attached to the same response to show in the
+	// embed.
+	Image string
+}
+
+// EmbedField is a titled section of an embed.
+type EmbedField struct {
+	Name   string
+	Value  string
+	Inline bool
+}
+
+// File is an attachment.
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Command describes a slash command, for registering with Discord.
+type Command struct {
+	Name        string
+	Description string
+	Options     []CommandOption
+}
+
+// CommandOption is a string option of a slash command.
+type CommandOption struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// Gateway is the bot's connection to Discord.
+type Gateway interface {
+	// RegisterCommands makes the bot's slash commands available.
+	RegisterCommands(commands []Command) error
+	// Interactions delivers slash commands as users run them. The channel
+	// is closed when the gateway disconnects.
+	Interactions() <-chan Interaction
+	// Respond replies to an interaction.
+	Respond(interaction Interaction, response Response) error
+}
+
+// Commands are the slash commands the bot answers.
+var Commands = []Command{
+	{
+		Name:        "race",
+		Description: "Record a race",
+		Options: []CommandOption{{
+			Name:        "results",
+			Description: `Players in finishing order, e.g. "alice,bob,carol"; join ties with "=", e.g. "alice,bob=carol"`,
+			Required:    true,
+		}},
+	},
+	{
+		Name:        "elo",
+		Description: "Show a player's rating and stats",
+		Options: []CommandOption{{
+			Name:        "player",
+			Description: "Player to show, yourself if left out",
+		}},
+	},
+	{
+		Name:        "leaderboard",
+		Description: "Show the leaderboard",
+	},
+	{
+		Name:        "graph",
+		Description: "Show everyone's rating over time",
+	},
+}
+
+// Bot answers slash commands against a league.
+type Bot struct {
+	mu      sync.Mutex
+	league  *multielo.League
+	gateway Gateway
+}
+
+// New returns a bot for league, talking to Discord through gateway.
+func New(league *multielo.League, gateway Gateway) *Bot {
+	return &Bot{league: league, gateway: gateway}
+}
+
+// Run registers the bot's commands and answers interactions until ctx is
+// cancelled or the gateway closes its interactions channel.
+func (b *Bot) Run(ctx context.Context) error {
+	if err := b.gateway.RegisterCommands(Commands); err != nil {
+		return fmt.Errorf("registering commands: %w", err)
+	}
+
+	interactions := b.gateway.Interactions()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case interaction, ok := <-interactions:
+			if !ok {
+				return nil
+			}
+
+			if err := b.gateway.Respond(interaction, b.Handle(interaction)); err != nil {
+				return fmt.Errorf("responding to %s: %w", interaction.ID, err)
+			}
+		}
+	}
+}
+
+// Handle answers a single interaction. Errors are reported back to the user
+// who ran the command.
+func (b *Bot) Handle(interaction Interaction) Response {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.league.SetActor(interaction.User)
+	defer b.league.SetActor("")
+
+	var response Response
+	var err error
+	switch interaction.Command {
+	case "race":
+		response, err = b.race(interaction)
+	case "elo":
+		response, err = b.elo(interaction)
+	case "leaderboard":
+		response, err = b.leaderboard()
+	case "graph":
+		response, err = b.graph()
+	default:
+		err = fmt.Errorf("%w: /%s", ErrUnknownCommand, interaction.Command)
+	}
+
+	if err != nil {
+		return Response{Content: "Error: " + err.Error(), Ephemeral: true}
+	}
+
+	return response
+}
+
+func (b *Bot) race(interaction Interaction) (Response, error) {
+	places := [][]string{}
+	for _, place := range strings.Split(interaction.Options["results"], ",") {
+		places = append(places, strings.Split(place, "="))
+	}
+
+	if _, err := b.league.AddMatchPlaces(places); err != nil {
+		return Response{}, err
+	}
+
+	match := b.league.Matches[len(b.league.Matches)-1]
+	return Response{Embeds: []Embed{MatchEmbed(b.league, match)}}, nil
+}
+
+func (b *Bot) elo(interaction Interaction) (Response, error) {
+	name := interaction.Options["player"]
+	if name == "" {
+		name = interaction.User
+	}
+
+	p, err := b.league.GetPlayer(name)
+	if err != nil {
+		return Response{}, fmt.Errorf("%q: %w", name, err)
+	}
+
+	return Response{Embeds: []Embed{PlayerEmbed(b.league, p)}}, nil
+}
+
+func (b *Bot) leaderboard() (Response, error) {
+	embed := LeaderboardEmbed(b.league)
+	if len(b.league.Leaderboard()) == 0 {
+		return Response{Embeds: []Embed{embed}}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := b.league.WriteLeaderboardImage(&buf, "png"); err != nil {
+		return Response{}, err
+	}
+
+	embed.Image = "leaderboard.png"
+	return Response{
+		Embeds: []Embed{embed},
+		Files:  []File{{Name: "leaderboard.png", ContentType: "image/png", Data: buf.Bytes()}},
+	}, nil
+}
+
+func (b *Bot) graph() (Response, error) {
+	var buf bytes.Buffer
+	if err := b.league.WriteGraph(&buf, "png"); err != nil {
+		return Response{}, err
+	}
+
+	return Response{
+		Embeds: []Embed{{Title: "ELO over time", Color: colorNeutral, Image: "elo.png"}},
+		Files:  []File{{Name: "elo.png", ContentType: "image/png", Data: buf.Bytes()}},
+	}, nil
+}
@@ -0,0 +1,172 @@
+package discord_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/distrobyte/multielo/discord"
+	"github.com/stretchr/testify/assert"
+)
+
+// startBot runs a bot for a league of the named players behind a fake
+// gateway, stopping it when the test ends.
+func startBot(t *testing.T, names ...string) (*discord.FakeGateway, *multielo.League) {
+	t.Helper()
+
+	l := multielo.NewLeague()
+	for _, name := range names {
+		assert.NoError(t, l.AddPlayer(name))
+	}
+
+	gateway := discord.NewFakeGateway()
+	done := make(chan error)
+	go func() {
+		done <- discord.New(l, gateway).Run(context.Background())
+	}()
+
+	t.Cleanup(func() {
+		gateway.Close()
+		assert.NoError(t, <-done)
+	})
+
+	return gateway, l
+}
+
+func command(user, name string, options map[string]string) discord.Interaction {
+	return discord.Interaction{ID: "1", User: user, Command: name, Options: options}
+}
+
+func TestBot(t *testing.T) {
+	t.Run("Race", func(t *testing.T) {
+		gateway, l := startBot(t, "alice", "bob", "carol")
+
+		response := gateway.Send(command("alice", "race", map[string]string{"results": "alice,bob=carol"}))
+		assert.False(t, response.Ephemeral)
+		assert.Len(t, response.Embeds, 1)
+		assert.Equal(t, "Race results", response.Embeds[0].Title)
+		assert.Equal(t, "alice wins", response.Embeds[0].Footer)
+
+		lines := strings.Split(strings.TrimSpace(response.Embeds[0].Description), "\n")
+		assert.Len(t, lines, 3)
+		assert.Equal(t, "1. **alice** 1016 ▲ +16", lines[0])
+		assert.Equal(t, "2. **bob** 992 ▼ -8", lines[1])
+		assert.Equal(t, "2. **carol** 992 ▼ -8", lines[2])
+
+		// a tie between equals changes nothing
+		response = gateway.Send(command("alice", "race", map[string]string{"results": "bob=carol"}))
+		assert.Equal(t, "1. **bob** 992 –\n1. **carol** 992 –\n", response.Embeds[0].Description)
+		assert.Equal(t, "bob and carol win", response.Embeds[0].Footer)
+
+		assert.Len(t, l.Matches, 2)
+		log := l.Log()
+		assert.Equal(t, "alice", log[len(log)-1].Actor)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		gateway, l := startBot(t, "alice")
+
+		response := gateway.Send(command("alice", "race", map[string]string{"results": "alice,mallory"}))
+		assert.True(t, response.Ephemeral)
+		assert.Contains(t, response.Content, multielo.ErrPlayerNotFound.Error())
+		assert.Empty(t, l.Matches)
+
+		response = gateway.Send(command("alice", "elo", map[string]string{"player": "mallory"}))
+		assert.True(t, response.Ephemeral)
+
+		response = gateway.Send(command("alice", "dance", nil))
+		assert.True(t, response.Ephemeral)
+		assert.Contains(t, response.Content, discord.ErrUnknownCommand.Error())
+	})
+
+	t.Run("ELO", func(t *testing.T) {
+		gateway, l := startBot(t, "alice", "bob")
+		_, err := l.AddMatchByName("bob", "alice")
+		assert.NoError(t, err)
+
+		response := gateway.Send(command("bob", "elo", nil))
+		assert.Len(t, response.Embeds, 1)
+		assert.Equal(t, "bob", response.Embeds[0].Title)
+		assert.Equal(t, discord.EmbedField{Name: "ELO", Value: "1016", Inline: true}, response.Embeds[0].Fields[0])
+
+		response = gateway.Send(command("bob", "elo", map[string]string{"player": "Alice"}))
+		assert.Equal(t, "alice", response.Embeds[0].Title)
+		assert.Contains(t, response.Embeds[0].Fields, discord.EmbedField{Name: "Last race", Value: "▼ -15", Inline: true})
+	})
+
+	t.Run("Leaderboard", func(t *testing.T) {
+		gateway, l := startBot(t)
+
+		response := gateway.Send(command("alice", "leaderboard", nil))
+		assert.Equal(t, "No players yet.", response.Embeds[0].Description)
+		assert.Empty(t, response.Files)
+
+		assert.NoError(t, l.AddPlayer("alice"))
+		assert.NoError(t, l.AddPlayer("bob"))
+		_, err := l.AddMatchByName("bob", "alice")
+		assert.NoError(t, err)
+
+		response = gateway.Send(command("alice", "leaderboard", nil))
+		assert.Equal(t, "1. **bob** 1016 ▲ +16\n2. **alice** 985 ▼ -15\n", response.Embeds[0].Description)
+		assert.Len(t, response.Files, 1)
+		assert.True(t, strings.HasPrefix(string(response.Files[0].Data), "\x89PNG"))
+		assert.Equal(t, "leaderboard.png", response.Embeds[0].Image)
+	})
+
+	t.Run("Graph", func(t *testing.T) {
+		gateway, _ := startBot(t, "alice", "bob")
+
+		response := gateway.Send(command("alice", "graph", nil))
+		assert.Len(t, response.Files, 1)
+		assert.Equal(t, "elo.png", response.Files[0].Name)
+		assert.True(t, strings.HasPrefix(string(response.Files[0].Data), "\x89PNG"))
+		assert.Equal(t, "elo.png", response.Embeds[0].Image)
+	})
+
+	t.Run("Commands", func(t *testing.T) {
+		gateway, _ := startBot(t)
+
+		// registration happens before the first interaction is read
+		gateway.Send(command("alice", "leaderboard", nil))
+		names := []string{}
+		for _, c := range gateway.Commands() {
+			names = append(names, c.Name)
+		}
+		assert.Equal(t, []string{"race", "elo", "leaderboard", "graph"}, names)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := discord.New(multielo.NewLeague(), discord.NewFakeGateway()).Run(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestEmbeds(t *testing.T) {
+	l := multielo.NewLeague()
+	for _, name := range []string{"alice", "bob", "carol"} {
+		assert.NoError(t, l.AddPlayer(name))
+	}
+
+	// results given last place first
+	diffs, err := l.AddMatch([]*multielo.MatchResult{
+		{Player: &multielo.Player{Name: "carol"}, Position: 3},
+		{Player: &multielo.Player{Name: "alice"}, Position: 1},
+		{Player: &multielo.Player{Name: "bob"}, Position: 2},
+	})
+	assert.NoError(t, err)
+
+	embed := discord.MatchEmbed(l, l.Matches[0])
+	assert.Equal(t, "alice wins", embed.Footer)
+	assert.True(t, strings.HasPrefix(embed.Description, "1. **alice**"))
+
+	embed = discord.DiffsEmbed(l, diffs)
+	assert.Equal(t, "Rating changes", embed.Title)
+	lines := strings.Split(strings.TrimSpace(embed.Description), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "**carol** "))
+	assert.Contains(t, lines[0], "▼")
+}
@@ -0,0 +1,127 @@
+package discord
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/distrobyte/multielo"
+)
+
+// Embed colours.
+const (
+	colorNeutral = 0x5865f2
+	colorGain    = 0x57f287
+	colorLoss    = 0xed4245
+)
+
+// MatchEmbed shows the rating changes from a recorded match, in finishing
+// order, naming every player who finished first in the footer.
+func MatchEmbed(league *multielo.League, match multielo.Match) Embed {
+	results := slices.Clone(match.Results)
+	slices.SortStableFunc(results, func(a, b *multielo.MatchResult) int {
+		return a.Position - b.Position
+	})
+
+	var description strings.Builder
+	winners := []string{}
+	for _, result := range results {
+		fmt.Fprintf(&description, "%d. **%s** %s %s\n", result.Position, result.Player.Name, league.FormatELO(result.Player), multielo.FormatChange(result.ELOChange))
+		if result.Position == 1 {
+			winners = append(winners, result.Player.Name)
+		}
+	}
+
+	embed := Embed{
+		Title:       "Race results",
+		Description: description.String(),
+		Color:       colorNeutral,
+	}
+
+	switch len(winners) {
+	case 0:
+	case 1:
+		embed.Footer = fmt.Sprintf("%s wins", winners[0])
+	default:
+		embed.Footer = fmt.Sprintf("%s and %s win", strings.Join(winners[:len(winners)-1], ", "), winners[len(winners)-1])
+	}
+
+	return embed
+}
+
+// DiffsEmbed shows the rating changes returned when a match is recorded, in
+// the order given. Diffs don't hold finishing positions, so use MatchEmbed to
+// show those.
+func DiffsEmbed(league *multielo.League, diffs []multielo.MatchDiff) Embed {
+	var description strings.Builder
+	for _, diff := range diffs {
+		fmt.Fprintf(&description, "**%s** %s %s\n", diff.Player.Name, league.FormatELO(diff.Player), multielo.FormatChange(diff.Diff))
+	}
+
+	return Embed{
+		Title:       "Rating changes",
+		Description: description.String(),
+		Color:       colorNeutral,
+	}
+}
+
+// LeaderboardEmbed shows the league's current standings.
+func LeaderboardEmbed(league *multielo.League) Embed {
+	leaderboard := league.Leaderboard()
+	if len(leaderboard) == 0 {
+		return Embed{Title: "Leaderboard", Description: "No players yet.", Color: colorNeutral}
+	}
+
+	var description strings.Builder
+	for i, p := range leaderboard {
+		fmt.Fprintf(&description, "%d. **%s** %s", i+1, p.Name, league.FormatELO(p))
+		if p.ELOChange != 0 {
+			fmt.Fprintf(&description, " %s", multielo.FormatChange(p.ELOChange))
+		}
+		description.WriteString("\n")
+	}
+
+	return Embed{
+		Title:       "Leaderboard",
+		Description: description.String(),
+		Color:       colorNeutral,
+	}
+}
+
+// PlayerEmbed shows a player's rating and stats.
+func PlayerEmbed(league *multielo.League, p *multielo.Player) Embed {
+	color := colorNeutral
+	switch {
+	case p.ELOChange > 0:
+		color = colorGain
+	case p.ELOChange < 0:
+		color = colorLoss
+	}
+
+	embed := Embed{
+		Title: p.Name,
+		Color: color,
+		Fields: []EmbedField{
+			{Name: "ELO", Value: league.FormatELO(p), Inline: true},
+			{Name: "Peak", Value: fmt.Sprint(p.Stats.PeakELO), Inline: true},
+			{Name: "Last race", Value: multielo.FormatChange(p.ELOChange), Inline: true},
+			{Name: "Played", Value: fmt.Sprint(p.Stats.MatchesPlayed), Inline: true},
+			{Name: "Won", Value: fmt.Sprint(p.Stats.MatchesWon), Inline: true},
+			{Name: "Win streak", Value: fmt.Sprint(p.Stats.WinStreak), Inline: true},
+		},
+	}
+
+	if len(p.Stats.Last5Finish) > 0 {
+		finishes := make([]string, 0, len(p.Stats.Last5Finish))
+		for _, position := range p.Stats.Last5Finish {
+			finishes = append(finishes, fmt.Sprint(position))
+		}
+		embed.Fields = append(embed.Fields, EmbedField{Name: "Recent finishes", Value: strings.Join(finishes, ", ")})
+	}
+
+	if league.IsProvisional(p) {
+		embed.Footer = "Provisional rating"
+	}
+
+	return embed
+}
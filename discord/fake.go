@@ -0,0 +1,66 @@
+package discord
+
+import (
+	"sync"
+)
+
+// FakeGateway is an in-memory Gateway for running a bot offline, such as in
+// tests. Send plays the part of a user running a command.
+type FakeGateway struct {
+	mu       sync.Mutex
+	commands []Command
+
+	interactions chan Interaction
+	responses    chan Response
+	closeOnce    sync.Once
+}
+
+// NewFakeGateway returns a fake gateway with no commands registered.
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{
+		interactions: make(chan Interaction),
+		responses:    make(chan Response),
+	}
+}
+
+// RegisterCommands records the commands.
+func (g *FakeGateway) RegisterCommands(commands []Command) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.commands = append([]Command{}, commands...)
+	return nil
+}
+
+// Commands returns the commands registered so far.
+func (g *FakeGateway) Commands() []Command {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return append([]Command{}, g.commands...)
+}
+
+// Interactions delivers the interactions passed to Send.
+func (g *FakeGateway) Interactions() <-chan Interaction {
+	return g.interactions
+}
+
+// Respond hands the response back to the waiting Send.
+func (g *FakeGateway) Respond(interaction Interaction, response Response) error {
+	g.responses <- response
+	return nil
+}
+
+// Send delivers an interaction to the bot and waits for its response. The
+// bot must be running.
+func (g *FakeGateway) Send(interaction Interaction) Response {
+	g.interactions <- interaction
+	return <-g.responses
+}
+
+// Close disconnects the gateway, stopping the bot.
+func (g *FakeGateway) Close() {
+	g.closeOnce.Do(func() {
+		close(g.interactions)
+	})
+}
@@ -0,0 +1,113 @@
+package multielo
+
+import (
+	"time"
+)
+
+// EventType names something that happened to a league.
+type EventType string
+
+const (
+	// EventPlayerAdded is sent when a player joins the league or is
+	// restored after retiring.
+	EventPlayerAdded EventType = "player_added"
+	// EventPlayerRemoved is sent when a player is retired or purged.
+	EventPlayerRemoved EventType = "player_removed"
+	// EventMatchRecorded is sent when a match is added, with every
+	// player's rating change.
+	EventMatchRecorded EventType = "match_recorded"
+	// EventRatingChanged is sent for each player whose rating moves, from
+	// a match, an undo or decay.
+	EventRatingChanged EventType = "rating_changed"
+	// EventPeakReached is sent when a player's rating passes their peak.
+	EventPeakReached EventType = "peak_reached"
+	// EventLeagueReset is sent when players or matches are reset, a season
+	// closes or the league is recalculated. Subscribers should re-read any
+	// state they keep.
+	EventLeagueReset EventType = "league_reset"
+)
+
+// Event describes a change to a league. Only the fields relevant to its Type
+// are set.
+type Event struct {
+	// Seq numbers a league's events from 1 in the order they happened.
+	Seq  uint64
+	Type EventType
+	Time time.Time
+
+	// Player is the player the event is about, for every type but
+	// EventMatchRecorded and EventLeagueReset.
+	Player *Player
+	// Match and Diffs are the recorded match and its rating changes, for
+	// EventMatchRecorded.
+	Match *Match
+	Diffs []MatchDiff
+	// ELO is the player's new rating, and Diff how much it moved, for
+	// EventRatingChanged and EventPeakReached.
+	ELO  int
+	Diff int
+}
+
+type subscriber struct {
+	id int
+	fn func(Event)
+}
+
+// Subscribe calls fn synchronously with every event the league sends from
+// now on, in order, before the call that caused it returns. It returns a
+// function that cancels the subscription.
+func (l *League) Subscribe(fn func(Event)) func() {
+	l.lastSubscriber++
+	id := l.lastSubscriber
+	l.subscribers = append(l.subscribers, subscriber{id: id, fn: fn})
+
+	return func() {
+		for i, s := range l.subscribers {
+			if s.id == id {
+				l.subscribers = append(l.subscribers[:i], l.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// SubscribeChan delivers every event the league sends from now on to a
+// channel with the given buffer size, in order. Nothing is dropped: once
+// the buffer is full the league blocks until the channel is read, so it must
+// be drained. The returned function cancels the subscription and closes the
+// channel.
+func (l *League) SubscribeChan(size int) (<-chan Event, func()) {
+	events := make(chan Event, size)
+	unsubscribe := l.Subscribe(func(e Event) {
+		events <- e
+	})
+
+	closed := false
+	return events, func() {
+		if !closed {
+			unsubscribe()
+			close(events)
+			closed = true
+		}
+	}
+}
+
+// emit sends an event to every subscriber.
+func (l *League) emit(e Event) {
+	l.eventSeq++
+	e.Seq = l.eventSeq
+	e.Time = time.Now()
+
+	// copy so subscribers can unsubscribe while being called
+	for _, s := range append([]subscriber{}, l.subscribers...) {
+		s.fn(e)
+	}
+}
+
+// emitRatingChanged sends EventRatingChanged for a player whose rating just
+// moved by diff.
+func (l *League) emitRatingChanged(p *Player, diff int) {
+	if diff != 0 {
+		l.emit(Event{Type: EventRatingChanged, Player: p, ELO: p.ELO, Diff: diff})
+	}
+}
@@ -0,0 +1,109 @@
+package multielo_test
+
+import (
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+// types returns the type of each event, in order.
+func types(events []multielo.Event) []multielo.EventType {
+	out := []multielo.EventType{}
+	for _, e := range events {
+		out = append(out, e.Type)
+	}
+
+	return out
+}
+
+func TestLeague_Subscribe(t *testing.T) {
+	t.Run("Subscribe", func(t *testing.T) {
+		l := multielo.NewLeague()
+
+		var events []multielo.Event
+		unsubscribe := l.Subscribe(func(e multielo.Event) {
+			events = append(events, e)
+		})
+
+		assert.NoError(t, l.AddPlayer("alice"))
+		assert.NoError(t, l.AddPlayer("bob"))
+		diff := recordMatch(t, l, "alice", "bob")
+		assert.NoError(t, l.RemovePlayer("bob"))
+		l.ResetPlayers()
+
+		assert.Equal(t, []multielo.EventType{
+			multielo.EventPlayerAdded,
+			multielo.EventPlayerAdded,
+			multielo.EventMatchRecorded,
+			multielo.EventRatingChanged,
+			multielo.EventPeakReached,
+			multielo.EventRatingChanged,
+			multielo.EventPlayerRemoved,
+			multielo.EventLeagueReset,
+		}, types(events))
+
+		for i, e := range events {
+			assert.Equal(t, uint64(i+1), e.Seq)
+			assert.False(t, e.Time.IsZero())
+		}
+
+		assert.Equal(t, "alice", events[0].Player.Name)
+		assert.Equal(t, diff, events[2].Diffs)
+		assert.Equal(t, l.Matches[0].ID, events[2].Match.ID)
+		assert.Equal(t, "alice", events[3].Player.Name)
+		assert.Equal(t, 16, events[3].Diff)
+		assert.Equal(t, 1016, events[3].ELO)
+		assert.Equal(t, 1016, events[4].ELO)
+		assert.Equal(t, -15, events[5].Diff)
+		assert.Equal(t, "bob", events[6].Player.Name)
+
+		unsubscribe()
+		assert.NoError(t, l.AddPlayer("carol"))
+		assert.Len(t, events, 8)
+	})
+
+	t.Run("Undo", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		recordMatch(t, l, "alice", "bob")
+
+		var events []multielo.Event
+		l.Subscribe(func(e multielo.Event) {
+			events = append(events, e)
+		})
+
+		_, err := l.UndoLastMatch()
+		assert.NoError(t, err)
+		assert.Equal(t, []multielo.EventType{multielo.EventRatingChanged, multielo.EventRatingChanged}, types(events))
+		assert.Equal(t, -16, events[0].Diff)
+		assert.Equal(t, multielo.InitialELO, events[0].ELO)
+	})
+
+	t.Run("SubscribeChan", func(t *testing.T) {
+		l := multielo.NewLeague()
+		events, unsubscribe := l.SubscribeChan(16)
+
+		assert.NoError(t, l.AddPlayer("alice"))
+		assert.NoError(t, l.AddPlayer("bob"))
+		recordMatch(t, l, "bob", "alice")
+		_, err := l.CloseSeason("january", 0)
+		assert.NoError(t, err)
+		unsubscribe()
+		unsubscribe()
+
+		var received []multielo.Event
+		for e := range events {
+			received = append(received, e)
+		}
+
+		assert.Equal(t, []multielo.EventType{
+			multielo.EventPlayerAdded,
+			multielo.EventPlayerAdded,
+			multielo.EventMatchRecorded,
+			multielo.EventRatingChanged,
+			multielo.EventPeakReached,
+			multielo.EventRatingChanged,
+			multielo.EventLeagueReset,
+		}, types(received))
+	})
+}
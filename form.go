@@ -0,0 +1,71 @@
+package multielo
+
+// podiumPlaces is how many finishing positions count as a podium.
+const podiumPlaces = 3
+
+// AveragePlace is the player's mean finishing position this season, or 0 if
+// they haven't played.
+func (s *PlayerStats) AveragePlace() float64 {
+	if s.MatchesPlayed == 0 {
+		return 0
+	}
+
+	return s.AllTimeAveragePlace / float64(s.MatchesPlayed)
+}
+
+// recordForm adds a finish in a match of n players to the player's recent
+// form, keeping only the last window matches, and updates their streaks.
+func (s *PlayerStats) recordForm(position, n, window int) {
+	s.Last5Finish = append(s.Last5Finish, position)
+	s.Last5FieldSize = append(s.Last5FieldSize, n)
+	if len(s.Last5Finish) > window {
+		s.Last5Finish = s.Last5Finish[len(s.Last5Finish)-window:]
+	}
+	if len(s.Last5FieldSize) > window {
+		s.Last5FieldSize = s.Last5FieldSize[len(s.Last5FieldSize)-window:]
+	}
+
+	s.FormScore, s.WeightedAverageFinish = s.form()
+
+	if position == 1 {
+		s.WinStreak++
+	} else {
+		s.WinStreak = 0
+	}
+	if s.WinStreak > s.LongestWinStreak {
+		s.LongestWinStreak = s.WinStreak
+	}
+
+	if position <= podiumPlaces {
+		s.PodiumStreak++
+	} else {
+		s.PodiumStreak = 0
+	}
+	if s.PodiumStreak > s.LongestPodiumStreak {
+		s.LongestPodiumStreak = s.PodiumStreak
+	}
+}
+
+// form returns the recency-weighted share of opponents beaten and mean
+// finishing position across the form window. The oldest match has a weight
+// of one, the next two, and so on up to the most recent.
+func (s *PlayerStats) form() (float64, float64) {
+	var score, finish, totalWeight float64
+
+	for i, position := range s.Last5Finish {
+		weight := float64(i + 1)
+		totalWeight += weight
+		finish += weight * float64(position)
+
+		// a field of one has no opponents to beat
+		if n := s.Last5FieldSize[i]; n > 1 {
+			score += weight * float64(n-position) / float64(n-1)
+		}
+	}
+
+	if totalWeight == 0 {
+		return 0, 0
+	}
+
+	return score / totalWeight, finish / totalWeight
+}
@@ -0,0 +1,76 @@
+package multielo_test
+
+import (
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayerStats_Form(t *testing.T) {
+	t.Run("Form", func(t *testing.T) {
+		l := multielo.NewLeague(multielo.WithFormWindow(3))
+		for _, name := range []string{"alice", "bob", "carol", "dave"} {
+			assert.NoError(t, l.AddPlayer(name))
+		}
+
+		recordMatch(t, l, "alice", "bob", "carol", "dave")
+		recordMatch(t, l, "alice", "carol", "bob", "dave")
+		recordMatch(t, l, "bob", "alice", "carol", "dave")
+		recordMatch(t, l, "bob", "carol", "dave", "alice")
+
+		stats, err := l.GetPlayerStats("alice")
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 4}, stats.Last5Finish)
+		assert.Equal(t, []int{4, 4, 4}, stats.Last5FieldSize)
+
+		// weights of 1, 2 and 3 from oldest to newest
+		assert.InDelta(t, (1*1.0+2*(2.0/3.0)+3*0.0)/6, stats.FormScore, 1e-9)
+		assert.InDelta(t, (1*1.0+2*2.0+3*4.0)/6, stats.WeightedAverageFinish, 1e-9)
+		assert.InDelta(t, (1+1+2+4)/4.0, stats.AveragePlace(), 1e-9)
+
+		carol, err := l.GetPlayerStats("carol")
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, (&multielo.PlayerStats{}).AveragePlace())
+		assert.InDelta(t, (3+2+3+2)/4.0, carol.AveragePlace(), 1e-9)
+
+		assert.Equal(t, 0, stats.WinStreak)
+		assert.Equal(t, 2, stats.LongestWinStreak)
+		assert.Equal(t, 0, stats.PodiumStreak)
+		assert.Equal(t, 3, stats.LongestPodiumStreak)
+
+		bob, err := l.GetPlayerStats("bob")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, bob.WinStreak)
+		assert.Equal(t, 4, bob.PodiumStreak)
+		assert.InDelta(t, (1*(1.0/3.0)+2*1.0+3*1.0)/6, bob.FormScore, 1e-9)
+	})
+
+	t.Run("FormEmpty", func(t *testing.T) {
+		l := newTestLeague(t, "alice")
+		stats, err := l.GetPlayerStats("alice")
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, stats.FormScore)
+		assert.Equal(t, 0.0, stats.WeightedAverageFinish)
+		assert.Empty(t, stats.Last5FieldSize)
+	})
+
+	t.Run("FormReplay", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol")
+		recordMatch(t, l, "alice", "bob", "carol")
+		recordMatch(t, l, "alice", "bob")
+		recordMatch(t, l, "carol", "alice", "bob")
+
+		alice, _ := l.GetPlayer("alice")
+		before := *alice.Stats
+
+		l.ResetPlayers()
+		assert.NoError(t, l.Recalculate())
+		assert.Equal(t, before, *alice.Stats)
+
+		_, err := l.UndoLastMatch()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, alice.Stats.WinStreak)
+		assert.Equal(t, 1.0, alice.Stats.FormScore)
+	})
+}
@@ -0,0 +1,258 @@
+package multielo
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TableFormat is a way of rendering a table of standings or results.
+type TableFormat string
+
+const (
+	// TableText is aligned plain text, for monospace output such as a
+	// terminal or a chat code block.
+	TableText TableFormat = "text"
+	// TableMarkdown is a Markdown table.
+	TableMarkdown TableFormat = "markdown"
+	// TableHTML is an HTML <table> fragment.
+	TableHTML TableFormat = "html"
+)
+
+// LeaderboardRow is a player's place on the leaderboard and how it has
+// changed since an earlier point.
+type LeaderboardRow struct {
+	Rank   int
+	Player *Player
+	// Change is how far the player's rating has moved since then.
+	Change int
+	// Movement is how many places the player has climbed since then, or
+	// dropped if negative.
+	Movement int
+}
+
+// LeaderboardSince returns the leaderboard with each player's rating change
+// and rank movement since the given time. A zero since compares with the
+// standings before the most recent match.
+func (l *League) LeaderboardSince(since time.Time) []LeaderboardRow {
+	leaderboard := l.Leaderboard()
+
+	// the matches that had been played by then
+	played := len(l.Matches) - 1
+	if !since.IsZero() {
+		played = 0
+		for played < len(l.Matches) && l.Matches[played].Date.Before(since) {
+			played++
+		}
+	}
+	played = max(played, 0)
+
+	before := map[*Player]int{}
+	for _, p := range leaderboard {
+		before[p] = p.StartingELO
+	}
+	for _, match := range l.Matches[:played] {
+		for _, result := range match.Results {
+			if _, ok := before[result.Player]; ok {
+				before[result.Player] = result.ELOBefore + result.ELOChange
+			}
+		}
+	}
+
+	previous := append([]*Player{}, leaderboard...)
+	sort.SliceStable(previous, func(i, j int) bool {
+		return before[previous[i]] > before[previous[j]]
+	})
+
+	previousRank := map[*Player]int{}
+	for i, p := range previous {
+		previousRank[p] = i + 1
+	}
+
+	rows := make([]LeaderboardRow, 0, len(leaderboard))
+	for i, p := range leaderboard {
+		rows = append(rows, LeaderboardRow{
+			Rank:     i + 1,
+			Player:   p,
+			Change:   p.ELO - before[p],
+			Movement: previousRank[p] - (i + 1),
+		})
+	}
+
+	return rows
+}
+
+// WriteLeaderboard renders the leaderboard to w, with rating changes and rank
+// movement since the given time as in LeaderboardSince.
+func (l *League) WriteLeaderboard(w io.Writer, format TableFormat, since time.Time) error {
+	t := table{
+		header: []string{"Rank", "Player", "ELO", "Change", "Move"},
+		right:  []bool{true, false, true, true, false},
+	}
+
+	for _, row := range l.LeaderboardSince(since) {
+		t.rows = append(t.rows, []string{
+			strconv.Itoa(row.Rank),
+			row.Player.Name,
+			l.FormatELO(row.Player),
+			FormatChange(row.Change),
+			formatMovement(row.Movement),
+		})
+	}
+
+	return t.write(w, format, "leaderboard")
+}
+
+// WriteMatchDiffs renders the rating changes from a match to w, in the order
+// given.
+func (l *League) WriteMatchDiffs(w io.Writer, format TableFormat, diffs []MatchDiff) error {
+	t := table{
+		header: []string{"Player", "ELO", "Change"},
+		right:  []bool{false, true, true},
+	}
+
+	for _, diff := range diffs {
+		t.rows = append(t.rows, []string{
+			diff.Player.Name,
+			l.FormatELO(diff.Player),
+			FormatChange(diff.Diff),
+		})
+	}
+
+	return t.write(w, format, "match")
+}
+
+// FormatChange formats a rating change with an arrow showing its direction.
+func FormatChange(diff int) string {
+	switch {
+	case diff > 0:
+		return fmt.Sprintf("▲ +%d", diff)
+	case diff < 0:
+		return fmt.Sprintf("▼ %d", diff)
+	}
+
+	return "–"
+}
+
+// formatMovement formats a change of rank.
+func formatMovement(places int) string {
+	switch {
+	case places > 0:
+		return fmt.Sprintf("▲%d", places)
+	case places < 0:
+		return fmt.Sprintf("▼%d", -places)
+	}
+
+	return "–"
+}
+
+// table is a table of text to render.
+type table struct {
+	header []string
+	// right holds whether each column is right aligned.
+	right []bool
+	rows  [][]string
+}
+
+// write renders the table. class is the HTML table's class attribute.
+func (t table) write(w io.Writer, format TableFormat, class string) error {
+	switch format {
+	case TableText:
+		return t.writeText(w)
+	case TableMarkdown:
+		return t.writeMarkdown(w)
+	case TableHTML:
+		return t.writeHTML(w, class)
+	}
+
+	return fmt.Errorf("unknown table format %q", format)
+}
+
+func (t table) writeText(w io.Writer) error {
+	lines := append([][]string{t.header}, t.rows...)
+
+	widths := make([]int, len(t.header))
+	for _, line := range lines {
+		for i, cell := range line {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		cells := make([]string, len(line))
+		for i, cell := range line {
+			padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if t.right[i] {
+				cells[i] = padding + cell
+			} else {
+				cells[i] = cell + padding
+			}
+		}
+		b.WriteString(strings.TrimRight(strings.Join(cells, "  "), " "))
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (t table) writeMarkdown(w io.Writer) error {
+	escape := strings.NewReplacer("|", `\|`)
+
+	var b strings.Builder
+	b.WriteString("|")
+	for _, cell := range t.header {
+		fmt.Fprintf(&b, " %s |", cell)
+	}
+	b.WriteString("\n|")
+	for _, right := range t.right {
+		if right {
+			b.WriteString(" ---: |")
+		} else {
+			b.WriteString(" --- |")
+		}
+	}
+	b.WriteString("\n")
+
+	for _, row := range t.rows {
+		b.WriteString("|")
+		for _, cell := range row {
+			fmt.Fprintf(&b, " %s |", escape.Replace(cell))
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (t table) writeHTML(w io.Writer, class string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<table class=%q>\n<thead>\n<tr>", class)
+	for _, cell := range t.header {
+		fmt.Fprintf(&b, "<th>%s</th>", html.EscapeString(cell))
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+
+	for _, row := range t.rows {
+		b.WriteString("<tr>")
+		for i, cell := range row {
+			if t.right[i] {
+				fmt.Fprintf(&b, `<td class="num">%s</td>`, html.EscapeString(cell))
+			} else {
+				fmt.Fprintf(&b, "<td>%s</td>", html.EscapeString(cell))
+			}
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,109 @@
+package multielo_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeague_LeaderboardSince(t *testing.T) {
+	l := newTestLeague(t, "alice", "bob", "carol")
+
+	rows := l.LeaderboardSince(time.Time{})
+	assert.Len(t, rows, 3)
+	assert.Equal(t, 0, rows[0].Change)
+	assert.Equal(t, 0, rows[0].Movement)
+
+	recordMatch(t, l, "alice", "bob", "carol")
+	recordMatch(t, l, "carol", "bob", "alice")
+
+	// since the last match
+	rows = l.LeaderboardSince(time.Time{})
+	names := []string{}
+	for _, row := range rows {
+		names = append(names, row.Player.Name)
+		assert.Equal(t, row.Player.ELOChange, row.Change)
+	}
+	assert.Equal(t, []string{"carol", "alice", "bob"}, names)
+	assert.Equal(t, []int{2, -1, -1}, []int{rows[0].Movement, rows[1].Movement, rows[2].Movement})
+	assert.Equal(t, 1, rows[0].Rank)
+
+	// since before either match
+	rows = l.LeaderboardSince(l.Matches[0].Date)
+	for _, row := range rows {
+		assert.Equal(t, row.Player.ELO-multielo.InitialELO, row.Change)
+		assert.Equal(t, 0, row.Movement)
+	}
+
+	// since after both
+	rows = l.LeaderboardSince(time.Now().Add(time.Hour))
+	for _, row := range rows {
+		assert.Equal(t, 0, row.Change)
+	}
+}
+
+func TestLeague_WriteLeaderboard(t *testing.T) {
+	l := newTestLeague(t, "alice", "bob")
+	recordMatch(t, l, "alice", "bob")
+	recordMatch(t, l, "bob", "alice")
+
+	write := func(format multielo.TableFormat) string {
+		var buf bytes.Buffer
+		assert.NoError(t, l.WriteLeaderboard(&buf, format, time.Time{}))
+		return buf.String()
+	}
+
+	assert.Equal(t, ""+
+		"Rank  Player   ELO  Change  Move\n"+
+		"   1  bob     1002   ▲ +17  ▲1\n"+
+		"   2  alice    999   ▼ -17  ▼1\n",
+		write(multielo.TableText))
+
+	assert.Equal(t, ""+
+		"| Rank | Player | ELO | Change | Move |\n"+
+		"| ---: | --- | ---: | ---: | --- |\n"+
+		"| 1 | bob | 1002 | ▲ +17 | ▲1 |\n"+
+		"| 2 | alice | 999 | ▼ -17 | ▼1 |\n",
+		write(multielo.TableMarkdown))
+
+	assert.Equal(t, ""+
+		"<table class=\"leaderboard\">\n"+
+		"<thead>\n"+
+		"<tr><th>Rank</th><th>Player</th><th>ELO</th><th>Change</th><th>Move</th></tr>\n"+
+		"</thead>\n"+
+		"<tbody>\n"+
+		"<tr><td class=\"num\">1</td><td>bob</td><td class=\"num\">1002</td><td class=\"num\">▲ +17</td><td>▲1</td></tr>\n"+
+		"<tr><td class=\"num\">2</td><td>alice</td><td class=\"num\">999</td><td class=\"num\">▼ -17</td><td>▼1</td></tr>\n"+
+		"</tbody>\n"+
+		"</table>\n",
+		write(multielo.TableHTML))
+
+	var buf bytes.Buffer
+	assert.Error(t, l.WriteLeaderboard(&buf, "pdf", time.Time{}))
+}
+
+func TestLeague_WriteMatchDiffs(t *testing.T) {
+	l := newTestLeague(t, "alice", "bob", "<carol>")
+	diffs := recordMatch(t, l, "<carol>", "alice", "bob")
+
+	var buf bytes.Buffer
+	assert.NoError(t, l.WriteMatchDiffs(&buf, multielo.TableText, diffs))
+	assert.Equal(t, ""+
+		"Player    ELO  Change\n"+
+		"<carol>  1016   ▲ +16\n"+
+		"alice    1000       –\n"+
+		"bob       984   ▼ -16\n",
+		buf.String())
+
+	buf.Reset()
+	assert.NoError(t, l.WriteMatchDiffs(&buf, multielo.TableHTML, diffs))
+	assert.Contains(t, buf.String(), `<table class="match">`)
+	assert.Contains(t, buf.String(), "<td>&lt;carol&gt;</td>")
+
+	buf.Reset()
+	assert.NoError(t, l.WriteMatchDiffs(&buf, multielo.TableMarkdown, diffs))
+	assert.Contains(t, buf.String(), "| <carol> | 1016 | ▲ +16 |\n")
+}
@@ -0,0 +1,667 @@
+package multielo
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// GraphOption changes how a graph is drawn.
+type GraphOption func(*graphOptions)
+
+type graphOptions struct {
+	median     bool
+	byDate     bool
+	players    []string
+	top        int
+	from, to   time.Time
+	season     string
+	labelEvery int
+}
+
+// GraphMedian adds the league's median rating to a player's graph for
+// comparison.
+func GraphMedian() GraphOption {
+	return func(o *graphOptions) {
+		o.median = true
+	}
+}
+
+// GraphByDate plots ratings against the date of each match rather than the
+// race number, so days without racing show as gaps.
+func GraphByDate() GraphOption {
+	return func(o *graphOptions) {
+		o.byDate = true
+	}
+}
+
+// GraphPlayers draws only the named players.
+func GraphPlayers(names ...string) GraphOption {
+	return func(o *graphOptions) {
+		o.players = append(o.players, names...)
+	}
+}
+
+// GraphTop draws only the n highest rated players.
+func GraphTop(n int) GraphOption {
+	return func(o *graphOptions) {
+		o.top = n
+	}
+}
+
+// GraphBetween draws only the matches played from from up to, but not
+// including, to, across every season. A zero from or to leaves that end open.
+// Players who didn't race in that time are left out.
+func GraphBetween(from, to time.Time) GraphOption {
+	return func(o *graphOptions) {
+		o.from, o.to = from, to
+	}
+}
+
+// GraphSeason draws a closed season instead of the current one. Players who
+// didn't race in it are left out.
+func GraphSeason(name string) GraphOption {
+	return func(o *graphOptions) {
+		o.season = name
+	}
+}
+
+// GraphLabelEvery labels each player's rating every n races, rather than
+// every third. Zero turns the labels off.
+func GraphLabelEvery(n int) GraphOption {
+	return func(o *graphOptions) {
+		o.labelEvery = n
+	}
+}
+
+func newGraphOptions(opts []GraphOption) graphOptions {
+	o := graphOptions{labelEvery: 3}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// graphMatches returns the matches a graph covers, and whether they are only
+// part of the current season's.
+func (l *League) graphMatches(o graphOptions) ([]Match, bool, error) {
+	matches, restricted := l.Matches, false
+	if o.season != "" {
+		season, err := l.GetSeason(o.season)
+		if err != nil {
+			return nil, false, fmt.Errorf("%q: %w", o.season, err)
+		}
+		matches, restricted = season.Matches, true
+	}
+
+	if o.from.IsZero() && o.to.IsZero() {
+		return matches, restricted, nil
+	}
+
+	if o.season == "" {
+		matches = l.AllMatches()
+	}
+
+	between := []Match{}
+	for _, match := range matches {
+		if (o.from.IsZero() || !match.Date.Before(o.from)) && (o.to.IsZero() || match.Date.Before(o.to)) {
+			between = append(between, match)
+		}
+	}
+
+	return between, true, nil
+}
+
+// graphPlayers returns the players a graph may draw: everyone in the league,
+// or everyone who raced in matches if they are only part of the season,
+// narrowed down to the players asked for.
+func (l *League) graphPlayers(o graphOptions, matches []Match, restricted bool) ([]*Player, error) {
+	players := l.Players
+	if restricted {
+		players = []*Player{}
+		seen := map[*Player]bool{}
+		for _, match := range matches {
+			for _, result := range match.Results {
+				if !seen[result.Player] {
+					seen[result.Player] = true
+					players = append(players, result.Player)
+				}
+			}
+		}
+	}
+
+	if len(o.players) == 0 {
+		return players, nil
+	}
+
+	find := func(name string) *Player {
+		for _, p := range players {
+			if strings.EqualFold(p.Name, name) {
+				return p
+			}
+		}
+		return nil
+	}
+
+	chosen := []*Player{}
+	for _, name := range o.players {
+		name = strings.TrimSpace(name)
+		if p := find(name); p != nil {
+			chosen = append(chosen, p)
+			continue
+		}
+
+		// a league player who didn't race in the period is simply left out
+		if _, err := l.GetPlayer(name); err != nil {
+			return nil, fmt.Errorf("%q: %w", name, err)
+		}
+	}
+
+	return chosen, nil
+}
+
+// ratingLine returns a player's rating after each of matches from their first
+// on, labelled as the options ask, and whether they raced in any of them.
+// A player who didn't race is plotted at their starting rating at the end.
+func (l *League) ratingLine(o graphOptions, matches []Match, player *Player) (plotter.XYs, []string, bool) {
+	var xys plotter.XYs
+	var labels []string
+	for i, match := range matches {
+		var result *MatchResult
+		for _, r := range match.Results {
+			if r.Player == player {
+				result = r
+				break
+			}
+		}
+
+		if len(xys) == 0 {
+			if result == nil {
+				continue
+			}
+
+			// add the starting ELO to the race before their first
+			xys = append(xys, plotter.XY{X: l.graphX(o, matches, i), Y: float64(result.ELOBefore)})
+			labels = append(labels, "")
+		}
+
+		// if they didn't race, their rating carries on from the last
+		y := xys[len(xys)-1].Y
+		if result != nil {
+			y = float64(result.ELOBefore + result.ELOChange)
+		}
+		xys = append(xys, plotter.XY{X: l.graphX(o, matches, i+1), Y: y})
+		labels = append(labels, "")
+
+		if o.labelEvery > 0 && i%o.labelEvery == 0 {
+			labels[len(labels)-1] = strconv.FormatFloat(y, 'f', 0, 64)
+		}
+	}
+
+	raced := len(xys) > 0
+	if !raced {
+		xys = plotter.XYs{{X: l.graphX(o, matches, len(matches)), Y: float64(player.StartingELO)}}
+		labels = []string{""}
+	}
+
+	// label the first and last points
+	if o.labelEvery > 0 {
+		labels[0] = strconv.FormatFloat(xys[0].Y, 'f', 0, 64)
+		labels[len(labels)-1] = strconv.FormatFloat(xys[len(xys)-1].Y, 'f', 0, 64)
+	}
+
+	return xys, labels, raced
+}
+
+// graphX returns where ratings after the first i of matches are plotted: i
+// itself, or with GraphByDate the time of the ith match as Unix seconds. The
+// ratings before any match are plotted at the start of the first match's
+// day.
+func (l *League) graphX(o graphOptions, matches []Match, i int) float64 {
+	if !o.byDate {
+		return float64(i)
+	}
+
+	switch {
+	case len(matches) == 0:
+		return float64(l.SeasonStart.Unix())
+	case i == 0:
+		date := matches[0].Date
+		return float64(time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location()).Unix())
+	}
+
+	return float64(matches[i-1].Date.Unix())
+}
+
+// graphStyle is how a player's line is drawn.
+type graphStyle struct {
+	color  color.Color
+	dashes []vg.Length
+	glyph  draw.GlyphDrawer
+}
+
+var (
+	// dashPatterns and glyphShapes tell apart players whose colours are
+	// alike, or the same once the palette runs out.
+	dashPatterns = [][]vg.Length{
+		nil,
+		{vg.Points(6), vg.Points(3)},
+		{vg.Points(1.5), vg.Points(2.5)},
+		{vg.Points(6), vg.Points(2), vg.Points(1.5), vg.Points(2)},
+	}
+
+	glyphShapes = []draw.GlyphDrawer{
+		draw.CircleGlyph{},
+		draw.BoxGlyph{},
+		draw.PyramidGlyph{},
+		draw.RingGlyph{},
+		draw.SquareGlyph{},
+		draw.TriangleGlyph{},
+		draw.CrossGlyph{},
+		draw.PlusGlyph{},
+	}
+)
+
+// assignStyle gives a player the next style, unless they already have one.
+func (l *League) assignStyle(p *Player) {
+	if p.style == 0 {
+		l.styles++
+		p.style = l.styles
+	}
+}
+
+// playerStyles returns the style each of the league's players is drawn in,
+// along with any others, such as purged players in a closed season.
+//
+// Players keep their style however a graph is filtered and whoever else
+// joins, leaves or is purged: it follows from the order they joined in. The
+// nth player takes the nth colour of the palette along with the nth dash
+// pattern and marker shape, and those shift along one each time the palette
+// comes round again, so no two players look the same until the palette has
+// been used once with every dash pattern. Pinned players are drawn in
+// their colour from Config.PlayerColors, and a player whose colour is pinned
+// to someone else takes the next colour that isn't.
+func (l *League) playerStyles(others ...*Player) (map[*Player]graphStyle, error) {
+	palette, err := l.Config.palette()
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := map[string]color.Color{}
+	for name, hex := range l.Config.PlayerColors {
+		c, err := parseColor(hex)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		pinned[strings.ToLower(name)] = c
+	}
+
+	isPinned := func(c color.Color) bool {
+		for _, pin := range pinned {
+			if sameColor(c, pin) {
+				return true
+			}
+		}
+		return false
+	}
+
+	players := append([]*Player{}, l.Players...)
+	for _, p := range others {
+		if !slices.Contains(players, p) {
+			players = append(players, p)
+		}
+	}
+
+	styles := map[*Player]graphStyle{}
+	unnumbered := l.styles
+	for _, p := range players {
+		// players built by hand rather than added are numbered after the rest
+		n := p.style
+		if n == 0 {
+			unnumbered++
+			n = unnumbered
+		}
+		n--
+
+		round := n%len(palette) + n/len(palette)
+		style := graphStyle{
+			color:  palette[n%len(palette)],
+			dashes: dashPatterns[round%len(dashPatterns)],
+			glyph:  glyphShapes[round%len(glyphShapes)],
+		}
+
+		if c, ok := pinned[strings.ToLower(p.Name)]; ok {
+			style.color = c
+		} else {
+			for i := 0; i < len(palette) && isPinned(style.color); i++ {
+				style.color = palette[(n+i+1)%len(palette)]
+			}
+		}
+		styles[p] = style
+	}
+
+	return styles, nil
+}
+
+func sameColor(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	return ar == br && ag == bg && ab == bb && aa == ba
+}
+
+var (
+	winColor    = color.RGBA{R: 255, G: 179, A: 255}
+	peakColor   = color.RGBA{R: 120, G: 120, B: 120, A: 255}
+	placeColor  = color.RGBA{R: 90, G: 90, B: 160, A: 255}
+	medianColor = color.RGBA{R: 160, G: 160, B: 160, A: 255}
+)
+
+// PlayerGraph plots one player's rating over time, with their wins marked,
+// a line at their peak rating and the average of their last few finishing
+// places on a second axis on the right. Save the plot or use WritePlayerGraph
+// to draw it. GraphPlayers, GraphTop and GraphLabelEvery don't apply to it.
+func (l *League) PlayerGraph(name string, opts ...GraphOption) (*plot.Plot, error) {
+	player, err := l.GetPlayer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	styles, err := l.playerStyles()
+	if err != nil {
+		return nil, err
+	}
+
+	o := newGraphOptions(opts)
+	window := l.Config.formWindow()
+
+	matches, _, err := l.graphMatches(o)
+	if err != nil {
+		return nil, err
+	}
+
+	// the player's rating after each match they played, starting from the
+	// rating they had before their first
+	var history, wins, places plotter.XYs
+	var recent []int
+	fieldSize := 1
+	for i, match := range matches {
+		for _, result := range match.Results {
+			if result.Player != player {
+				continue
+			}
+
+			if len(history) == 0 {
+				history = append(history, plotter.XY{X: l.graphX(o, matches, i), Y: float64(result.ELOBefore)})
+			}
+
+			point := plotter.XY{X: l.graphX(o, matches, i+1), Y: float64(result.ELOBefore + result.ELOChange)}
+			history = append(history, point)
+			if result.Position == 1 {
+				wins = append(wins, point)
+			}
+
+			recent = append(recent, result.Position)
+			if len(recent) > window {
+				recent = recent[1:]
+			}
+			places = append(places, plotter.XY{X: point.X, Y: mean(recent)})
+			fieldSize = max(fieldSize, len(match.Results))
+		}
+	}
+
+	if len(history) == 0 {
+		history = append(history, plotter.XY{X: l.graphX(o, matches, len(matches)), Y: float64(player.StartingELO)})
+	}
+
+	var medians plotter.XYs
+	if o.median {
+		medians = l.medianHistory(o, matches, history[0].X)
+	}
+
+	// the y axis fits the player's ratings and the median
+	peak, low, high := history[0].Y, history[0].Y, history[0].Y
+	for _, xy := range history {
+		peak = math.Max(peak, xy.Y)
+	}
+	for _, xy := range append(append(plotter.XYs{}, history...), medians...) {
+		low = math.Min(low, xy.Y)
+		high = math.Max(high, xy.Y)
+	}
+
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("%s: ELO over time", player.Name)
+	p.X.Label.Text = "Races"
+	p.Y.Label.Text = "ELO"
+	p.Add(plotter.NewGrid())
+
+	p.X.Tick.Marker = RaceTicker{}
+	p.Y.Tick.Marker = ELOTicker{}
+	if o.byDate {
+		p.X.Label.Text = "Date"
+		p.X.Tick.Marker = plot.TimeTicks{Format: "2 Jan"}
+	}
+	p.X.Min = history[0].X
+	p.Y.Min = low - 50
+	p.Y.Max = high + 50
+
+	if len(places) > 0 {
+		axis := placeAxis{min: p.Y.Min, max: p.Y.Max, places: fieldSize}
+		for i := range places {
+			places[i].Y = axis.y(places[i].Y)
+		}
+
+		line, err := plotter.NewLine(places)
+		if err != nil {
+			return nil, err
+		}
+		line.Color = placeColor
+		line.Dashes = []vg.Length{vg.Points(2), vg.Points(2)}
+
+		p.Add(line, axis)
+		p.Legend.Add(fmt.Sprintf("Average place (last %d)", window), line)
+		p.Legend.XOffs = -axis.width()
+	}
+
+	if len(medians) > 0 {
+		line, err := plotter.NewLine(medians)
+		if err != nil {
+			return nil, err
+		}
+		line.Color = medianColor
+		line.Width = vg.Points(2)
+
+		p.Add(line)
+		p.Legend.Add("League median", line)
+	}
+
+	last := history[len(history)-1]
+	peakLine, err := plotter.NewLine(plotter.XYs{{X: history[0].X, Y: peak}, {X: last.X, Y: peak}})
+	if err != nil {
+		return nil, err
+	}
+	peakLine.Color = peakColor
+	peakLine.Dashes = []vg.Length{vg.Points(6), vg.Points(3)}
+	p.Add(peakLine)
+	p.Legend.Add(fmt.Sprintf("Peak (%.0f)", peak), peakLine)
+
+	line, points, err := plotter.NewLinePoints(history)
+	if err != nil {
+		return nil, err
+	}
+	style := styles[player]
+	line.Color = style.color
+	line.Dashes = style.dashes
+	points.Color = style.color
+	points.Shape = style.glyph
+	p.Add(line, points)
+	p.Legend.Add(fmt.Sprintf("%s (%s)", player.Name, l.FormatELO(player)), line, points)
+
+	if len(wins) > 0 {
+		scatter, err := plotter.NewScatter(wins)
+		if err != nil {
+			return nil, err
+		}
+		scatter.Color = winColor
+		scatter.Shape = draw.PyramidGlyph{}
+		scatter.Radius = vg.Points(5)
+
+		p.Add(scatter)
+		p.Legend.Add("Win", scatter)
+	}
+
+	return p, nil
+}
+
+// WritePlayerGraph draws PlayerGraph to w in the given format, such as "png"
+// or "svg".
+func (l *League) WritePlayerGraph(w io.Writer, format, name string, opts ...GraphOption) error {
+	p, err := l.PlayerGraph(name, opts...)
+	if err != nil {
+		return err
+	}
+
+	writer, err := p.WriterTo(graphWidth, graphHeight, format)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.WriteTo(w)
+	return err
+}
+
+// medianHistory returns the median rating of the players who had played by
+// each of matches, leaving out the points plotted before from.
+func (l *League) medianHistory(o graphOptions, matches []Match, from float64) plotter.XYs {
+	ratings := map[*Player]int{}
+	var xys plotter.XYs
+	for i := 0; i <= len(matches); i++ {
+		if i > 0 {
+			for _, result := range matches[i-1].Results {
+				ratings[result.Player] = result.ELOBefore + result.ELOChange
+			}
+		}
+
+		x := l.graphX(o, matches, i)
+		if x < from || len(ratings) == 0 {
+			continue
+		}
+
+		values := make([]float64, 0, len(ratings))
+		for _, rating := range ratings {
+			values = append(values, float64(rating))
+		}
+		xys = append(xys, plotter.XY{X: x, Y: median(values)})
+	}
+
+	return xys
+}
+
+func mean(values []int) float64 {
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+
+	return float64(sum) / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// placeAxis is an axis of finishing places drawn on the right of a plot,
+// with first place at the top. Places are plotted against the plot's own y
+// axis, scaled by y.
+type placeAxis struct {
+	// min and max are the range of the plot's y axis.
+	min, max float64
+	// places is the lowest place on the axis.
+	places int
+}
+
+// y returns where place falls on the plot's y axis.
+func (a placeAxis) y(place float64) float64 {
+	if a.places <= 1 {
+		return a.max
+	}
+
+	return a.max - (place-1)/float64(a.places-1)*(a.max-a.min)
+}
+
+func (a placeAxis) style() draw.TextStyle {
+	return draw.TextStyle{
+		Color:   color.Black,
+		Font:    font.From(plot.DefaultFont, 10),
+		XAlign:  draw.XLeft,
+		YAlign:  draw.YCenter,
+		Handler: plot.DefaultTextHandler,
+	}
+}
+
+// step returns how many places apart the axis's labels are, so there are no
+// more than ten.
+func (a placeAxis) step() int {
+	return max(1, (a.places+9)/10)
+}
+
+const (
+	placeAxisTick    = 5
+	placeAxisPadding = 3
+)
+
+// width returns how much room the axis needs to the right of the plot.
+func (a placeAxis) width() vg.Length {
+	style := a.style()
+	return placeAxisTick + 2*placeAxisPadding + style.Width(strconv.Itoa(a.places)) + style.Height("Average place")
+}
+
+// Plot implements the plot.Plotter interface.
+func (a placeAxis) Plot(c draw.Canvas, plt *plot.Plot) {
+	_, trY := plt.Transforms(&c)
+	style := a.style()
+	line := draw.LineStyle{Color: color.Black, Width: vg.Points(0.5)}
+
+	x := c.Max.X
+	c.StrokeLine2(line, x, c.Min.Y, x, c.Max.Y)
+
+	for place := 1; place <= a.places; place += a.step() {
+		y := trY(a.y(float64(place)))
+		c.StrokeLine2(line, x, y, x+placeAxisTick, y)
+		c.FillText(style, vg.Point{X: x + placeAxisTick + placeAxisPadding, Y: y}, strconv.Itoa(place))
+	}
+
+	label := style
+	label.XAlign = draw.XCenter
+	label.YAlign = draw.YTop
+	label.Rotation = -math.Pi / 2
+	c.FillText(label, vg.Point{X: x + a.width(), Y: (c.Min.Y + c.Max.Y) / 2}, "Average place")
+}
+
+// GlyphBoxes implements the plot.GlyphBoxer interface, keeping room for the
+// axis to the right of the plot.
+func (a placeAxis) GlyphBoxes(*plot.Plot) []plot.GlyphBox {
+	return []plot.GlyphBox{{
+		X:         1,
+		Y:         0.5,
+		Rectangle: vg.Rectangle{Max: vg.Point{X: a.width(), Y: 0}},
+	}}
+}
@@ -0,0 +1,261 @@
+package multielo_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeague_PlayerGraph(t *testing.T) {
+	l := newTestLeague(t, "alice", "bob", "carol")
+	recordMatch(t, l, "alice", "bob", "carol")
+	recordMatch(t, l, "bob", "alice", "carol")
+	recordMatch(t, l, "alice", "carol")
+
+	t.Run("Plot", func(t *testing.T) {
+		p, err := l.PlayerGraph("alice")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice: ELO over time", p.Title.Text)
+		assert.Equal(t, 0.0, p.X.Min)
+		assert.Equal(t, 3.0, p.X.Max)
+	})
+
+	t.Run("SVG", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, l.WritePlayerGraph(&buf, "svg", "alice"))
+
+		alice, err := l.GetPlayer("alice")
+		assert.NoError(t, err)
+
+		svg := buf.String()
+		assert.Contains(t, svg, fmt.Sprintf("alice (%d)", alice.ELO))
+		assert.Contains(t, svg, fmt.Sprintf("Peak (%d)", alice.Stats.PeakELO))
+		assert.Contains(t, svg, "Win")
+		assert.Contains(t, svg, "Average place (last 5)")
+		assert.NotContains(t, svg, "League median")
+
+		buf.Reset()
+		assert.NoError(t, l.WritePlayerGraph(&buf, "svg", "carol", multielo.GraphMedian()))
+		svg = buf.String()
+		assert.Contains(t, svg, "League median")
+		assert.NotContains(t, svg, ">Win<")
+	})
+
+	t.Run("PNG", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, l.WritePlayerGraph(&buf, "png", "bob", multielo.GraphMedian()))
+		assert.True(t, strings.HasPrefix(buf.String(), "\x89PNG"))
+	})
+
+	t.Run("NoMatches", func(t *testing.T) {
+		assert.NoError(t, l.AddPlayer("dave"))
+
+		p, err := l.PlayerGraph("dave", multielo.GraphMedian())
+		assert.NoError(t, err)
+		assert.Equal(t, 3.0, p.X.Min)
+	})
+
+	t.Run("UnknownPlayer", func(t *testing.T) {
+		_, err := l.PlayerGraph("mallory")
+		assert.ErrorIs(t, err, multielo.ErrPlayerNotFound)
+	})
+}
+
+func TestLeague_GraphOptions(t *testing.T) {
+	l := newTestLeague(t, "alice", "bob", "carol")
+	recordMatch(t, l, "alice", "bob", "carol")
+	recordMatch(t, l, "bob", "alice")
+	recordMatch(t, l, "bob", "alice")
+
+	day := time.Date(2024, time.March, 1, 19, 0, 0, 0, time.UTC)
+	l.Matches[0].Date = day
+	l.Matches[1].Date = day.Add(time.Hour)
+	l.Matches[2].Date = day.AddDate(0, 0, 7)
+
+	svg := func(t *testing.T, opts ...multielo.GraphOption) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		assert.NoError(t, l.WriteGraph(&buf, "svg", opts...))
+		return buf.String()
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		graph := svg(t)
+		for _, name := range []string{"alice", "bob", "carol"} {
+			assert.Contains(t, graph, name+" (")
+		}
+		assert.Contains(t, graph, "Races")
+		assert.Contains(t, graph, ">1016<")
+	})
+
+	t.Run("Top", func(t *testing.T) {
+		graph := svg(t, multielo.GraphTop(2))
+		assert.Contains(t, graph, "bob (")
+		assert.Contains(t, graph, "alice (")
+		assert.NotContains(t, graph, "carol (")
+	})
+
+	t.Run("Players", func(t *testing.T) {
+		graph := svg(t, multielo.GraphPlayers("Carol"))
+		assert.Contains(t, graph, "carol (")
+		assert.NotContains(t, graph, "alice (")
+
+		var buf bytes.Buffer
+		err := l.WriteGraph(&buf, "svg", multielo.GraphPlayers("mallory"))
+		assert.ErrorIs(t, err, multielo.ErrPlayerNotFound)
+	})
+
+	t.Run("NoLabels", func(t *testing.T) {
+		assert.NotContains(t, svg(t, multielo.GraphLabelEvery(0)), ">1016<")
+	})
+
+	t.Run("ByDate", func(t *testing.T) {
+		graph := svg(t, multielo.GraphByDate())
+		assert.Contains(t, graph, ">Date<")
+		assert.NotContains(t, graph, ">Races<")
+
+		p, err := l.PlayerGraph("alice", multielo.GraphByDate())
+		assert.NoError(t, err)
+		assert.Equal(t, float64(time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC).Unix()), p.X.Min)
+		assert.Equal(t, float64(l.Matches[2].Date.Unix()), p.X.Max)
+	})
+
+	t.Run("Between", func(t *testing.T) {
+		// carol only raced on the first day
+		alice, err := l.GetPlayer("alice")
+		assert.NoError(t, err)
+
+		graph := svg(t, multielo.GraphBetween(day.AddDate(0, 0, 1), time.Time{}))
+		assert.NotContains(t, graph, "carol (")
+		assert.Contains(t, graph, fmt.Sprintf("alice (%d)", alice.ELO))
+
+		graph = svg(t, multielo.GraphBetween(time.Time{}, day.Add(time.Minute)))
+		assert.Contains(t, graph, "carol (")
+		assert.Contains(t, graph, fmt.Sprintf("alice (%d)", l.Matches[0].Results[0].ELOBefore+l.Matches[0].Results[0].ELOChange))
+
+		var buf bytes.Buffer
+		err = l.WriteGraph(&buf, "svg", multielo.GraphBetween(day.AddDate(1, 0, 0), time.Time{}))
+		assert.ErrorIs(t, err, multielo.ErrNoPlayers)
+	})
+
+	t.Run("Season", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol")
+		recordMatch(t, l, "carol", "bob")
+		_, err := l.CloseSeason("spring", 0)
+		assert.NoError(t, err)
+		recordMatch(t, l, "alice", "bob")
+
+		var buf bytes.Buffer
+		assert.NoError(t, l.WriteGraph(&buf, "svg", multielo.GraphSeason("spring")))
+		assert.Contains(t, buf.String(), "carol (1016)")
+		assert.NotContains(t, buf.String(), "alice (")
+
+		err = l.WriteGraph(&buf, "svg", multielo.GraphSeason("summer"))
+		assert.ErrorIs(t, err, multielo.ErrSeasonNotFound)
+	})
+}
+
+func TestLeague_GraphStyles(t *testing.T) {
+	svg := func(t *testing.T, l *multielo.League, opts ...multielo.GraphOption) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		assert.NoError(t, l.WriteGraph(&buf, "svg", opts...))
+		return buf.String()
+	}
+
+	t.Run("Stable", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol")
+		recordMatch(t, l, "alice", "bob", "carol")
+
+		// players keep the colour they joined with, whatever their rank
+		// and whoever else is drawn
+		assert.Contains(t, svg(t, l, multielo.GraphPlayers("carol")), "stroke:#008856")
+		recordMatch(t, l, "carol", "bob", "alice")
+		recordMatch(t, l, "carol", "bob", "alice")
+		assert.Contains(t, svg(t, l, multielo.GraphTop(1)), "stroke:#008856")
+		assert.Contains(t, svg(t, l, multielo.GraphPlayers("alice")), "stroke:#0067A5")
+	})
+
+	t.Run("LargeLeague", func(t *testing.T) {
+		names := []string{}
+		for i := 0; i < 22; i++ {
+			names = append(names, fmt.Sprintf("player%02d", i))
+		}
+		l := newTestLeague(t, names...)
+
+		// the 21st player has the first colour again, but the next dashes
+		graph := svg(t, l, multielo.GraphPlayers("player20"))
+		assert.Contains(t, graph, "stroke:#0067A5;stroke-dasharray:6,3")
+
+		graph = svg(t, l, multielo.GraphPlayers("player00"))
+		assert.Contains(t, graph, "stroke:#0067A5")
+		assert.NotContains(t, graph, "stroke-dasharray")
+	})
+
+	t.Run("Dashes", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol")
+
+		// dashes tell lines apart well before the palette runs out
+		assert.Contains(t, svg(t, l, multielo.GraphPlayers("bob")), "stroke:#BE0032;stroke-dasharray:6,3")
+		assert.Contains(t, svg(t, l, multielo.GraphPlayers("carol")), "stroke:#008856;stroke-dasharray:1.5,2.5")
+	})
+
+	t.Run("Purged", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol")
+		recordMatch(t, l, "alice", "bob", "carol")
+		bob := "stroke:#BE0032;stroke-dasharray:6,3"
+		carol := "stroke:#008856;stroke-dasharray:1.5,2.5"
+
+		// purging a player leaves everyone else's style, and their style
+		// isn't given to the next player to join
+		assert.NoError(t, l.PurgePlayer("alice"))
+		assert.NoError(t, l.AddPlayer("dave"))
+		assert.Contains(t, svg(t, l, multielo.GraphPlayers("bob")), bob)
+		assert.Contains(t, svg(t, l, multielo.GraphPlayers("carol")), carol)
+		assert.Contains(t, svg(t, l, multielo.GraphPlayers("dave")), "stroke:#F38400")
+
+		// however the league is saved and rebuilt
+		decoded, _ := roundTrip(t, l)
+		assert.Contains(t, svg(t, decoded, multielo.GraphPlayers("carol")), carol)
+		assert.Contains(t, svg(t, decoded, multielo.GraphPlayers("dave")), "stroke:#F38400")
+		rebuilt, err := multielo.ReplayLog(l.Log())
+		assert.NoError(t, err)
+		assert.Contains(t, svg(t, rebuilt, multielo.GraphPlayers("carol")), carol)
+		assert.Contains(t, svg(t, rebuilt, multielo.GraphPlayers("dave")), "stroke:#F38400")
+	})
+
+	t.Run("Pinned", func(t *testing.T) {
+		l := multielo.NewLeague(multielo.WithPlayerColor("Bob", "#0067a5"))
+		for _, name := range []string{"alice", "bob"} {
+			assert.NoError(t, l.AddPlayer(name))
+		}
+
+		// alice skips the colour bob has pinned
+		assert.Contains(t, svg(t, l, multielo.GraphPlayers("bob")), "stroke:#0067A5")
+		assert.Contains(t, svg(t, l, multielo.GraphPlayers("alice")), "stroke:#BE0032")
+
+		var buf bytes.Buffer
+		assert.NoError(t, l.WritePlayerGraph(&buf, "svg", "bob"))
+		assert.Contains(t, buf.String(), "stroke:#0067A5")
+	})
+
+	t.Run("PinnedStable", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol")
+		bob := svg(t, l, multielo.GraphPlayers("bob"))
+
+		// pinning carol to alice's colour moves alice on, but nobody else
+		config := l.Config
+		config.PlayerColors = map[string]string{"carol": "#0067a5"}
+		assert.NoError(t, l.Configure(config))
+		assert.Equal(t, bob, svg(t, l, multielo.GraphPlayers("bob")))
+		assert.Contains(t, svg(t, l, multielo.GraphPlayers("carol")), "stroke:#0067A5")
+		assert.Contains(t, svg(t, l, multielo.GraphPlayers("alice")), "stroke:#BE0032")
+	})
+}
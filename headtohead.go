@@ -0,0 +1,156 @@
+package multielo
+
+// HeadToHead is the record of one player against another across every match
+// they both took part in. All figures are from PlayerA's point of view.
+type HeadToHead struct {
+	PlayerA string
+	PlayerB string
+
+	// Matches is the number of matches both players finished.
+	Matches int
+	// Wins, Losses and Ties count how often PlayerA finished ahead of,
+	// behind or level with PlayerB.
+	Wins   int
+	Losses int
+	Ties   int
+	// AveragePositionGap is the mean of PlayerB's position minus PlayerA's,
+	// so a positive value means PlayerA usually finishes ahead.
+	AveragePositionGap float64
+	// RatingExchanged is the net ELO PlayerA has gained from their results
+	// against PlayerB, as recorded when each match was rated. A negative
+	// value means PlayerA has lost rating to PlayerB. It can differ a little
+	// from the opposite of PlayerB's figure, as whichever of them comes
+	// later in a match's results is scored against the other's rating after
+	// the match, and provisional players' K values differ.
+	RatingExchanged int
+}
+
+// RivalryMatrix holds the head-to-head record of every pair of players in a
+// league. Records[i][j] is Players[i]'s record against Players[j]; the
+// diagonal is left empty.
+type RivalryMatrix struct {
+	Players []string
+	Records [][]HeadToHead
+}
+
+// HeadToHead returns a's record against b, computed from the league's
+// matches.
+func (l *League) HeadToHead(a, b string) (*HeadToHead, error) {
+	playerA, err := l.GetPlayer(a)
+	if err != nil {
+		return nil, err
+	}
+
+	playerB, err := l.GetPlayer(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if playerA == playerB {
+		return nil, ErrInvalidPlayer
+	}
+
+	h2h := l.headToHead(l.Matches, playerA.Name, playerB.Name)
+	return &h2h, nil
+}
+
+// CareerHeadToHead is like HeadToHead but covers every closed season as well
+// as the current one.
+func (l *League) CareerHeadToHead(a, b string) (*HeadToHead, error) {
+	h2h, err := l.HeadToHead(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	career := l.headToHead(l.AllMatches(), h2h.PlayerA, h2h.PlayerB)
+	return &career, nil
+}
+
+// RivalryMatrix returns the head-to-head record of every player against every
+// other player, in the same order as Players.
+func (l *League) RivalryMatrix() *RivalryMatrix {
+	matrix := &RivalryMatrix{
+		Players: make([]string, len(l.Players)),
+		Records: make([][]HeadToHead, len(l.Players)),
+	}
+
+	for i, player := range l.Players {
+		matrix.Players[i] = player.Name
+		matrix.Records[i] = make([]HeadToHead, len(l.Players))
+	}
+
+	for i := range l.Players {
+		for j := range l.Players {
+			if i != j {
+				matrix.Records[i][j] = l.headToHead(l.Matches, l.Players[i].Name, l.Players[j].Name)
+			}
+		}
+	}
+
+	return matrix
+}
+
+// Get returns a's record against b, or false if either player is not in the
+// matrix.
+func (m *RivalryMatrix) Get(a, b string) (HeadToHead, bool) {
+	i, j := -1, -1
+	for k, name := range m.Players {
+		if name == a {
+			i = k
+		}
+		if name == b {
+			j = k
+		}
+	}
+
+	if i < 0 || j < 0 || i == j {
+		return HeadToHead{}, false
+	}
+
+	return m.Records[i][j], true
+}
+
+func (l *League) headToHead(matches []Match, a, b string) HeadToHead {
+	h2h := HeadToHead{PlayerA: a, PlayerB: b}
+	positionGap := 0
+
+	for _, match := range matches {
+		var resultA, resultB *MatchResult
+		for _, result := range match.Results {
+			if result.Player == nil {
+				continue
+			}
+
+			switch result.Player.Name {
+			case a:
+				resultA = result
+			case b:
+				resultB = result
+			}
+		}
+
+		if resultA == nil || resultB == nil {
+			continue
+		}
+
+		h2h.Matches++
+		positionGap += resultB.Position - resultA.Position
+
+		switch {
+		case resultA.Position < resultB.Position:
+			h2h.Wins++
+		case resultA.Position > resultB.Position:
+			h2h.Losses++
+		default:
+			h2h.Ties++
+		}
+
+		h2h.RatingExchanged += resultA.Exchanges[b]
+	}
+
+	if h2h.Matches > 0 {
+		h2h.AveragePositionGap = float64(positionGap) / float64(h2h.Matches)
+	}
+
+	return h2h
+}
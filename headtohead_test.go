@@ -0,0 +1,140 @@
+package multielo_test
+
+import (
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestLeague returns a league with the given players already added.
+func newTestLeague(t *testing.T, names ...string) *multielo.League {
+	t.Helper()
+
+	l := multielo.NewLeague()
+	for _, name := range names {
+		assert.NoError(t, l.AddPlayer(name))
+	}
+
+	return l
+}
+
+// recordMatch adds a match where the players finish in the order given.
+func recordMatch(t *testing.T, l *multielo.League, names ...string) []multielo.MatchDiff {
+	t.Helper()
+
+	diff, err := l.AddMatchByName(names...)
+	assert.NoError(t, err)
+
+	return diff
+}
+
+func TestLeague_HeadToHead(t *testing.T) {
+	t.Run("HeadToHead", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol")
+		recordMatch(t, l, "alice", "bob", "carol")
+		recordMatch(t, l, "bob", "carol", "alice")
+		recordMatch(t, l, "alice", "carol", "bob")
+		recordMatch(t, l, "alice", "carol")
+
+		h2h, err := l.HeadToHead("alice", "bob")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", h2h.PlayerA)
+		assert.Equal(t, "bob", h2h.PlayerB)
+		assert.Equal(t, 3, h2h.Matches)
+		assert.Equal(t, 2, h2h.Wins)
+		assert.Equal(t, 1, h2h.Losses)
+		assert.Equal(t, 0, h2h.Ties)
+		assert.InDelta(t, 1.0/3.0, h2h.AveragePositionGap, 1e-9)
+		assert.Greater(t, h2h.RatingExchanged, 0)
+
+		reversed, err := l.HeadToHead("Bob", "Alice")
+		assert.NoError(t, err)
+		assert.Equal(t, h2h.Wins, reversed.Losses)
+		assert.Equal(t, -h2h.RatingExchanged, reversed.RatingExchanged)
+	})
+
+	t.Run("HeadToHeadTie", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		alice, _ := l.GetPlayer("alice")
+		bob, _ := l.GetPlayer("bob")
+
+		_, err := l.AddMatch([]*multielo.MatchResult{
+			{Player: alice, Position: 1},
+			{Player: bob, Position: 1},
+		})
+		assert.NoError(t, err)
+
+		h2h, err := l.HeadToHead("alice", "bob")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, h2h.Ties)
+		assert.Equal(t, 0, h2h.RatingExchanged)
+	})
+
+	t.Run("HeadToHeadPlayerNotFound", func(t *testing.T) {
+		l := newTestLeague(t, "alice")
+		_, err := l.HeadToHead("alice", "bob")
+		assert.Equal(t, multielo.ErrPlayerNotFound, err)
+	})
+
+	t.Run("HeadToHeadSamePlayer", func(t *testing.T) {
+		l := newTestLeague(t, "alice")
+		_, err := l.HeadToHead("alice", "alice")
+		assert.Equal(t, multielo.ErrInvalidPlayer, err)
+	})
+}
+
+func TestLeague_RivalryMatrix(t *testing.T) {
+	l := newTestLeague(t, "alice", "bob", "carol")
+	recordMatch(t, l, "alice", "bob", "carol")
+	recordMatch(t, l, "carol", "bob")
+
+	matrix := l.RivalryMatrix()
+	assert.Equal(t, []string{"alice", "bob", "carol"}, matrix.Players)
+	assert.Len(t, matrix.Records, 3)
+
+	for i, name := range matrix.Players {
+		for j, opponent := range matrix.Players {
+			if i == j {
+				continue
+			}
+
+			h2h, err := l.HeadToHead(name, opponent)
+			assert.NoError(t, err)
+			assert.Equal(t, *h2h, matrix.Records[i][j])
+		}
+	}
+
+	carolBob, ok := matrix.Get("carol", "bob")
+	assert.True(t, ok)
+	assert.Equal(t, 2, carolBob.Matches)
+	assert.Equal(t, 1, carolBob.Wins)
+	assert.Equal(t, 1, carolBob.Losses)
+
+	_, ok = matrix.Get("alice", "alice")
+	assert.False(t, ok)
+}
+
+func TestLeague_HeadToHeadMatchesRecordedChanges(t *testing.T) {
+	l := newTestLeague(t, "alice", "bob")
+	diff := recordMatch(t, l, "alice", "bob")
+
+	h2h, err := l.HeadToHead("alice", "bob")
+	assert.NoError(t, err)
+	assert.Equal(t, diff[0].Diff, h2h.RatingExchanged)
+	assert.Equal(t, 16, h2h.RatingExchanged)
+
+	// changing the settings later doesn't rewrite history
+	assert.NoError(t, l.Configure(multielo.Config{KFactor: 64}))
+	_, err = l.CloseSeason("january", 1)
+	assert.NoError(t, err)
+
+	career, err := l.CareerHeadToHead("alice", "bob")
+	assert.NoError(t, err)
+	assert.Equal(t, 16, career.RatingExchanged)
+
+	decoded, _ := roundTrip(t, l)
+	career, err = decoded.CareerHeadToHead("bob", "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, -15, career.RatingExchanged)
+}
@@ -0,0 +1,231 @@
+package multielo
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// LogOp names a change recorded in a league's log.
+type LogOp string
+
+const (
+	// OpCreateLeague is always the first entry, recording the league's
+	// Config and when its first season started.
+	OpCreateLeague LogOp = "create_league"
+	// OpConfigure replaces the league's Config.
+	OpConfigure LogOp = "configure"
+	// OpAddPlayer adds a player with a starting rating.
+	OpAddPlayer LogOp = "add_player"
+	// OpRemovePlayer retires a player.
+	OpRemovePlayer LogOp = "remove_player"
+	// OpRestorePlayer brings a retired player back.
+	OpRestorePlayer LogOp = "restore_player"
+	// OpPurgePlayer deletes a player and their results.
+	OpPurgePlayer LogOp = "purge_player"
+	// OpAddMatch records a match.
+	OpAddMatch LogOp = "add_match"
+	// OpUndoMatch removes the most recent match.
+	OpUndoMatch LogOp = "undo_match"
+	// OpResetPlayers, OpResetMatches and OpRecalculate record the
+	// League methods of the same names.
+	OpResetPlayers LogOp = "reset_players"
+	OpResetMatches LogOp = "reset_matches"
+	OpRecalculate  LogOp = "recalculate"
+	// OpCloseSeason closes the current season.
+	OpCloseSeason LogOp = "close_season"
+	// OpImport replaces the league with a snapshot. It follows
+	// OpCreateLeague in the log of a league read from a file that had none.
+	OpImport LogOp = "import"
+)
+
+// LogEntry is one change to a league. Only the fields relevant to its Op are
+// set.
+type LogEntry struct {
+	// Seq numbers a league's entries from 1 in the order they happened.
+	Seq int   `json:"seq"`
+	Op  LogOp `json:"op"`
+	// Actor is who made the change, as set with SetActor.
+	Actor string    `json:"actor,omitempty"`
+	Time  time.Time `json:"time"`
+
+	// Player and ELO are the player changed and, for OpAddPlayer, their
+	// starting rating.
+	Player string `json:"player,omitempty"`
+	ELO    int    `json:"elo,omitempty"`
+	// Results is the finishing order of an OpAddMatch.
+	Results []LogResult `json:"results,omitempty"`
+	// Season and CarryOver are the arguments to CloseSeason.
+	Season    string  `json:"season,omitempty"`
+	CarryOver float64 `json:"carry_over,omitempty"`
+	// Config is the league's configuration, for OpCreateLeague and
+	// OpConfigure.
+	Config *Config `json:"config,omitempty"`
+	// Snapshot is the league for OpImport, encoded as described at
+	// SchemaVersion without its log.
+	Snapshot json.RawMessage `json:"snapshot,omitempty"`
+}
+
+// LogResult is one player's finishing position in a logged match.
+type LogResult struct {
+	Player   string `json:"player"`
+	Position int    `json:"position"`
+}
+
+// SetActor sets who is making changes to the league, recorded against every
+// log entry from now on.
+func (l *League) SetActor(actor string) {
+	l.actor = actor
+}
+
+// Log returns a copy of every change made to the league, oldest first.
+// Passing it to ReplayLog rebuilds the league.
+func (l *League) Log() []LogEntry {
+	entries := make([]LogEntry, len(l.log))
+	for i, entry := range l.log {
+		entries[i] = entry.copy()
+	}
+
+	return entries
+}
+
+// Configure replaces the league's configuration. Ratings already recorded are
+// left alone; call Recalculate to re-rate them under the new Config.
+func (l *League) Configure(config Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	l.Config = config
+	l.record(LogEntry{Op: OpConfigure, Config: &config})
+	return nil
+}
+
+// ReplayLog rebuilds a league by re-applying every entry of a log taken from
+// League.Log. The new league's log is the one it was built from, so it can
+// carry on recording where the original left off.
+func ReplayLog(entries []LogEntry) (*League, error) {
+	if len(entries) == 0 || entries[0].Op != OpCreateLeague || entries[0].Config == nil {
+		return nil, fmt.Errorf("%w: log must start with %s", ErrInvalidLeague, OpCreateLeague)
+	}
+
+	config := *entries[0].Config
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	l := &League{
+		Players:     []*Player{},
+		Matches:     []Match{},
+		Config:      config,
+		Seasons:     []Season{},
+		SeasonStart: entries[0].Time,
+		log:         []LogEntry{entries[0].copy()},
+	}
+
+	for _, entry := range entries[1:] {
+		l.actor = entry.Actor
+		if err := l.replay(entry); err != nil {
+			return nil, fmt.Errorf("replaying log entry %d (%s): %w", entry.Seq, entry.Op, err)
+		}
+
+		// keep the original entry, with its time and sequence number
+		l.log[len(l.log)-1] = entry.copy()
+	}
+	l.actor = ""
+
+	return l, nil
+}
+
+// replay applies a single log entry.
+func (l *League) replay(entry LogEntry) error {
+	switch entry.Op {
+	case OpConfigure:
+		if entry.Config == nil {
+			return fmt.Errorf("%w: missing config", ErrInvalidConfig)
+		}
+		return l.Configure(*entry.Config)
+	case OpAddPlayer:
+		return l.AddPlayerWithRating(entry.Player, entry.ELO)
+	case OpRemovePlayer:
+		return l.RemovePlayer(entry.Player)
+	case OpRestorePlayer:
+		return l.RestorePlayer(entry.Player)
+	case OpPurgePlayer:
+		return l.PurgePlayer(entry.Player)
+	case OpAddMatch:
+		results := make([]*MatchResult, len(entry.Results))
+		for i, result := range entry.Results {
+			results[i] = &MatchResult{Player: &Player{Name: result.Player}, Position: result.Position}
+		}
+		_, err := l.addMatch(results, entry.Time)
+		return err
+	case OpUndoMatch:
+		_, err := l.UndoLastMatch()
+		return err
+	case OpResetPlayers:
+		l.ResetPlayers()
+		return nil
+	case OpResetMatches:
+		l.ResetMatches()
+		return nil
+	case OpRecalculate:
+		return l.Recalculate()
+	case OpCloseSeason:
+		_, err := l.closeSeason(entry.Season, entry.CarryOver, entry.Time)
+		return err
+	case OpImport:
+		return l.importSnapshot(entry.Snapshot)
+	}
+
+	return fmt.Errorf("%w: unknown log op %q", ErrInvalidLeague, entry.Op)
+}
+
+// record appends an entry to the league's log. Entries without a time are
+// stamped with the current time.
+func (l *League) record(entry LogEntry) {
+	entry.Seq = len(l.log) + 1
+	entry.Actor = l.actor
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	l.log = append(l.log, entry.copy())
+}
+
+// copy returns a deep copy of the entry, so the log can't be changed through
+// what it hands out.
+func (e LogEntry) copy() LogEntry {
+	if e.Results != nil {
+		e.Results = append([]LogResult{}, e.Results...)
+	}
+
+	if e.Snapshot != nil {
+		e.Snapshot = append(json.RawMessage{}, e.Snapshot...)
+	}
+
+	if e.Config != nil {
+		config := *e.Config
+		if config.Colors != nil {
+			config.Colors = append([]string{}, config.Colors...)
+		}
+		if config.PlayerColors != nil {
+			colors := map[string]string{}
+			for name, hex := range config.PlayerColors {
+				colors[name] = hex
+			}
+			config.PlayerColors = colors
+		}
+		if config.Decay != nil {
+			decay := *config.Decay
+			config.Decay = &decay
+		}
+		if config.Provisional != nil {
+			provisional := *config.Provisional
+			config.Provisional = &provisional
+		}
+		e.Config = &config
+	}
+
+	return e
+}
@@ -0,0 +1,143 @@
+package multielo_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeague_Log(t *testing.T) {
+	t.Run("Log", func(t *testing.T) {
+		l := multielo.NewLeague()
+		l.SetActor("alice")
+		assert.NoError(t, l.AddPlayer("alice"))
+		assert.NoError(t, l.AddPlayerWithRating("bob", 1200))
+		l.SetActor("bob")
+		recordMatch(t, l, "bob", "alice")
+		assert.NoError(t, l.RemovePlayer("bob"))
+
+		log := l.Log()
+		ops := []multielo.LogOp{}
+		for i, entry := range log {
+			assert.Equal(t, i+1, entry.Seq)
+			assert.False(t, entry.Time.IsZero())
+			ops = append(ops, entry.Op)
+		}
+
+		assert.Equal(t, []multielo.LogOp{
+			multielo.OpCreateLeague,
+			multielo.OpAddPlayer,
+			multielo.OpAddPlayer,
+			multielo.OpAddMatch,
+			multielo.OpRemovePlayer,
+		}, ops)
+
+		assert.Equal(t, "", log[0].Actor)
+		assert.Equal(t, "alice", log[1].Actor)
+		assert.Equal(t, 1200, log[2].ELO)
+		assert.Equal(t, "bob", log[3].Actor)
+		assert.Equal(t, []multielo.LogResult{{Player: "bob", Position: 1}, {Player: "alice", Position: 2}}, log[3].Results)
+		assert.Equal(t, l.Matches[0].Date, log[3].Time)
+		assert.Equal(t, "bob", log[4].Player)
+
+		// the log can't be changed through what Log returns
+		log[3].Results[0].Player = "mallory"
+		log[0].Config.KFactor = 1
+		assert.Equal(t, "bob", l.Log()[3].Results[0].Player)
+		assert.Equal(t, multielo.DefaultKFactor, l.Log()[0].Config.KFactor)
+	})
+
+	t.Run("FailedChangesAreNotLogged", func(t *testing.T) {
+		l := newTestLeague(t, "alice")
+		assert.Error(t, l.AddPlayer("alice"))
+		assert.Error(t, l.RemovePlayer("bob"))
+		_, err := l.UndoLastMatch()
+		assert.Error(t, err)
+		assert.Error(t, l.Configure(multielo.Config{KFactor: -1}))
+
+		assert.Len(t, l.Log(), 2)
+	})
+
+	t.Run("ReplayLog", func(t *testing.T) {
+		l := multielo.NewLeague(multielo.WithKFactor(40), multielo.WithProvisional(multielo.ProvisionalPolicy{Matches: 2, KMultiplier: 2}))
+		for _, name := range []string{"alice", "bob", "carol", "dave"} {
+			assert.NoError(t, l.AddPlayer(name))
+		}
+
+		recordMatch(t, l, "alice", "bob", "carol", "dave")
+		recordMatch(t, l, "carol", "alice", "bob")
+		recordMatch(t, l, "dave", "carol")
+		_, err := l.UndoLastMatch()
+		assert.NoError(t, err)
+		_, err = l.CloseSeason("january", 0.5)
+		assert.NoError(t, err)
+
+		assert.NoError(t, l.Configure(multielo.Config{KFactor: 16}))
+		recordMatch(t, l, "bob", "dave", "alice")
+		assert.NoError(t, l.RemovePlayer("carol"))
+		assert.NoError(t, l.PurgePlayer("dave"))
+		assert.NoError(t, l.AddPlayer("erin"))
+		recordMatch(t, l, "erin", "alice", "bob")
+
+		// round trip through JSON, as a log would be stored
+		data, err := json.Marshal(l.Log())
+		assert.NoError(t, err)
+		var log []multielo.LogEntry
+		assert.NoError(t, json.Unmarshal(data, &log))
+
+		replayed, err := multielo.ReplayLog(log)
+		assert.NoError(t, err)
+
+		assert.Equal(t, l.Config, replayed.Config)
+		replayedData, err := json.Marshal(replayed.Log())
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(data), string(replayedData))
+		assert.Equal(t, l.SeasonStart.Unix(), replayed.SeasonStart.Unix())
+		assert.Len(t, replayed.Players, len(l.Players))
+		for i, p := range l.Players {
+			assert.Equal(t, p.Name, replayed.Players[i].Name)
+			assert.Equal(t, p.ELO, replayed.Players[i].ELO)
+			assert.Equal(t, p.StartingELO, replayed.Players[i].StartingELO)
+			assert.Equal(t, p.Retired, replayed.Players[i].Retired)
+			assert.Equal(t, *p.Stats, *replayed.Players[i].Stats)
+			assert.Equal(t, kinds(p.Achievements), kinds(replayed.Players[i].Achievements))
+		}
+
+		assert.Len(t, replayed.Matches, len(l.Matches))
+		for i, match := range l.Matches {
+			assert.Equal(t, match.ID, replayed.Matches[i].ID)
+			for j, result := range match.Results {
+				assert.Equal(t, result.Player.Name, replayed.Matches[i].Results[j].Player.Name)
+				assert.Equal(t, result.ELOChange, replayed.Matches[i].Results[j].ELOChange)
+			}
+		}
+
+		assert.Len(t, replayed.Seasons, 1)
+		assert.Equal(t, l.Seasons[0].Name, replayed.Seasons[0].Name)
+		assert.Equal(t, l.Seasons[0].Standings, replayed.Seasons[0].Standings)
+
+		// the replayed league carries on logging where the original stopped
+		replayed.SetActor("bob")
+		recordMatch(t, replayed, "alice", "bob")
+		log = replayed.Log()
+		assert.Equal(t, len(l.Log())+1, log[len(log)-1].Seq)
+		assert.Equal(t, "bob", log[len(log)-1].Actor)
+	})
+
+	t.Run("ReplayLogInvalid", func(t *testing.T) {
+		_, err := multielo.ReplayLog(nil)
+		assert.ErrorIs(t, err, multielo.ErrInvalidLeague)
+
+		log := multielo.NewLeague().Log()
+		log = append(log, multielo.LogEntry{Seq: 2, Op: "explode"})
+		_, err = multielo.ReplayLog(log)
+		assert.ErrorIs(t, err, multielo.ErrInvalidLeague)
+
+		log = multielo.NewLeague().Log()
+		log = append(log, multielo.LogEntry{Seq: 2, Op: multielo.OpRemovePlayer, Player: "alice"})
+		_, err = multielo.ReplayLog(log)
+		assert.ErrorIs(t, err, multielo.ErrPlayerNotFound)
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"image/color"
+	"io"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -12,30 +14,52 @@ import (
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
-	"gonum.org/v1/plot/vg/draw"
 )
 
 var (
 	ErrPlayerAlreadyExists = errors.New("player already exists")
 	ErrPlayerNotFound      = errors.New("player not found")
+	ErrPlayerRetired       = errors.New("player is retired")
 	ErrMatchNotFound       = errors.New("match not found")
 	ErrInvalidMatch        = errors.New("invalid match")
 	ErrInvalidELOChange    = errors.New("invalid elo change")
 	ErrInvalidPlayer       = errors.New("invalid player")
 	ErrInvalidPlayerStats  = errors.New("invalid player stats")
 	ErrInvalidLeague       = errors.New("invalid league")
+	ErrInvalidSeason       = errors.New("invalid season")
+	ErrInvalidConfig       = errors.New("invalid config")
+	ErrSeasonNotFound      = errors.New("season not found")
 	ErrNoPlayers           = errors.New("no players")
-	colors                 = []color.Color{
-		color.RGBA{R: 255, A: 255},
-		color.RGBA{G: 255, A: 255},
-		color.RGBA{B: 255, A: 255},
-		color.RGBA{R: 255, G: 255, A: 255},
-		color.RGBA{R: 255, B: 255, A: 255},
-		color.RGBA{G: 255, B: 255, A: 255},
+
+	// colors is the default palette: Kelly's colours of maximum contrast,
+	// without white and black, most distinct first.
+	colors = []color.Color{
+		color.RGBA{R: 0x00, G: 0x67, B: 0xa5, A: 0xff},
+		color.RGBA{R: 0xbe, G: 0x00, B: 0x32, A: 0xff},
+		color.RGBA{R: 0x00, G: 0x88, B: 0x56, A: 0xff},
+		color.RGBA{R: 0xf3, G: 0x84, B: 0x00, A: 0xff},
+		color.RGBA{R: 0x87, G: 0x56, B: 0x92, A: 0xff},
+		color.RGBA{R: 0xa1, G: 0xca, B: 0xf1, A: 0xff},
+		color.RGBA{R: 0x88, G: 0x2d, B: 0x17, A: 0xff},
+		color.RGBA{R: 0xe6, G: 0x8f, B: 0xac, A: 0xff},
+		color.RGBA{R: 0x8d, G: 0xb6, B: 0x00, A: 0xff},
+		color.RGBA{R: 0x60, G: 0x4e, B: 0x97, A: 0xff},
+		color.RGBA{R: 0xf6, G: 0xa6, B: 0x00, A: 0xff},
+		color.RGBA{R: 0xb3, G: 0x44, B: 0x6c, A: 0xff},
+		color.RGBA{R: 0x2b, G: 0x3d, B: 0x26, A: 0xff},
+		color.RGBA{R: 0xe2, G: 0x58, B: 0x22, A: 0xff},
+		color.RGBA{R: 0x65, G: 0x45, B: 0x22, A: 0xff},
+		color.RGBA{R: 0xc2, G: 0xb2, B: 0x80, A: 0xff},
+		color.RGBA{R: 0x84, G: 0x84, B: 0x82, A: 0xff},
+		color.RGBA{R: 0xf9, G: 0x93, B: 0x79, A: 0xff},
+		color.RGBA{R: 0xdc, G: 0xd3, B: 0x00, A: 0xff},
+		color.RGBA{R: 0xf3, G: 0xc3, B: 0x00, A: 0xff},
 	}
 )
 
 const (
+	// InitialELO is the rating new players start on unless the league's
+	// Config says otherwise.
 	InitialELO = 1000
 )
 
@@ -44,17 +68,58 @@ type Player struct {
 	ELO       int
 	ELOChange int
 	Stats     *PlayerStats
+
+	// StartingELO is the rating the player began the current season on.
+	// Resets and replays of the season's matches start from here.
+	StartingELO int
+
+	// Retired players keep their history but can't enter new matches and
+	// are left off the leaderboard.
+	Retired bool
+
+	// Achievements lists every milestone the player has reached, oldest
+	// first, across all seasons.
+	Achievements []Achievement
+
+	// style numbers the players from 1 in the order they joined, choosing
+	// how they are drawn in graphs. 0 means not yet numbered.
+	style int
 }
 
 type PlayerStats struct {
-	MatchesPlayed       int
-	MatchesWon          int
+	MatchesPlayed int
+	MatchesWon    int
+	// AllTimeAveragePlace is, despite its name, the sum of the player's
+	// finishing positions this season. AveragePlace divides it out.
 	AllTimeAveragePlace float64
 	Last5Finish         []int
 	PeakELO             int
+
+	// Last5FieldSize is how many players were in each match in
+	// Last5Finish. Despite their names, both hold as many matches as the
+	// league's form window.
+	Last5FieldSize []int
+
+	// FormScore is the recency-weighted share of opponents the player beat
+	// across the form window, from 0 (last every time) to 1 (won every time).
+	// WeightedAverageFinish is the recency-weighted mean finishing position
+	// across the same matches.
+	FormScore             float64
+	WeightedAverageFinish float64
+
+	// WinStreak and PodiumStreak count the player's current run of
+	// consecutive wins and top three finishes, and the Longest fields their
+	// best runs.
+	WinStreak           int
+	LongestWinStreak    int
+	PodiumStreak        int
+	LongestPodiumStreak int
 }
 
 type Match struct {
+	// ID identifies the match within its league. IDs count up from 1 and
+	// are never reused across seasons.
+	ID      int
 	Results []*MatchResult
 	Date    time.Time
 }
@@ -62,6 +127,18 @@ type Match struct {
 type MatchResult struct {
 	Position int
 	Player   *Player
+
+	// ELOBefore and ELOChange are filled in when the match is recorded and
+	// hold the player's rating going into the match and how much it moved.
+	// Provisional records whether the player's rating was still
+	// provisional at the time.
+	ELOBefore   int
+	ELOChange   int
+	Provisional bool
+
+	// Exchanges splits ELOChange by opponent, keyed by their name, so it
+	// adds up to ELOChange.
+	Exchanges map[string]int
 }
 
 type MatchDiff struct {
@@ -72,140 +149,494 @@ type MatchDiff struct {
 type League struct {
 	Players []*Player
 	Matches []Match
+
+	Config Config
+
+	// Seasons holds every closed season, oldest first. Matches only holds
+	// the current season's matches.
+	Seasons     []Season
+	SeasonStart time.Time
+
+	// OnAchievement, if set, is called for each achievement a player earns
+	// once the match that earned it has been recorded. It isn't called when
+	// achievements are worked out again by Recalculate.
+	OnAchievement func(*Player, Achievement) `json:"-"`
+
+	subscribers    []subscriber
+	lastSubscriber int
+	eventSeq       uint64
+
+	actor string
+	log   []LogEntry
+
+	// archived counts each player's results in the first archivedSeasons
+	// closed seasons, by name.
+	archived        map[string]int
+	archivedSeasons int
+
+	// styles is the last style given to a player. Purged players' styles
+	// aren't given out again.
+	styles int
 }
 
-func NewLeague() *League {
-	return &League{
-		Players: []*Player{},
-		Matches: []Match{},
+// NewLeague creates an empty league. Its settings start from DefaultConfig
+// and are then adjusted by each option in turn.
+func NewLeague(opts ...Option) *League {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
 	}
+
+	l := &League{
+		Players:     []*Player{},
+		Matches:     []Match{},
+		Config:      config,
+		Seasons:     []Season{},
+		SeasonStart: time.Now(),
+	}
+	l.record(LogEntry{Op: OpCreateLeague, Time: l.SeasonStart, Config: &config})
+
+	return l
 }
 
+// AddMatch records a match and re-rates everyone in it. Results are matched
+// to the league's players by name; the results and players passed in are
+// never changed. The diffs and the recorded match refer to the league's own
+// players.
 func (l *League) AddMatch(results []*MatchResult) ([]MatchDiff, error) {
+	return l.addMatch(results, time.Now())
+}
+
+// AddMatchByName records a match from the names of the players in finishing
+// order, winner first.
+func (l *League) AddMatchByName(names ...string) ([]MatchDiff, error) {
+	places := make([][]string, 0, len(names))
+	for _, name := range names {
+		places = append(places, []string{name})
+	}
+
+	return l.AddMatchPlaces(places)
+}
+
+// AddMatchPlaces records a match from the names of the players in each
+// finishing place, first place first. Players sharing a place tied, so
+// [][]string{{"alice"}, {"bob", "carol"}} has bob and carol tied for second.
+func (l *League) AddMatchPlaces(places [][]string) ([]MatchDiff, error) {
+	results, err := resultsFromPlaces(places)
+	if err != nil {
+		return []MatchDiff{}, err
+	}
+
+	return l.AddMatch(results)
+}
+
+// resultsFromPlaces turns finishing places into match results.
+func resultsFromPlaces(places [][]string) ([]*MatchResult, error) {
+	results := []*MatchResult{}
+	for i, place := range places {
+		if len(place) == 0 {
+			return nil, fmt.Errorf("place %d is empty: %w", i+1, ErrInvalidMatch)
+		}
+
+		for _, name := range place {
+			results = append(results, &MatchResult{
+				Player:   &Player{Name: strings.ToLower(strings.TrimSpace(name))},
+				Position: i + 1,
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// addMatch records a match played at date.
+func (l *League) addMatch(results []*MatchResult, date time.Time) ([]MatchDiff, error) {
 	if len(l.Players) == 0 {
 		return nil, ErrNoPlayers
 	}
 
-	populatedResults := make([]*MatchResult, 0, len(results))
+	// ensure all players are registered
+	players, err := l.resolveResults(results)
+	if err != nil {
+		return []MatchDiff{}, err
+	}
+
+	// record the league's own copy of the results, so the caller's results
+	// and players are never touched, bringing any inactive players' ratings
+	// up to date first
+	recorded := make([]*MatchResult, 0, len(results))
+	for i, result := range results {
+		players[i].ELO = l.ratingAt(players[i], date)
+		recorded = append(recorded, &MatchResult{
+			Position:    result.Position,
+			Player:      players[i],
+			ELOBefore:   players[i].ELO,
+			Provisional: l.IsProvisional(players[i]),
+		})
+	}
+	results = recorded
+
+	// calculate the ELO changes
+	changes := l.rateResults(results)
 	matchDiff := make([]MatchDiff, 0, len(results))
 
-	// ensure all players are registered and flesh out the results with ELOs
 	for _, result := range results {
-		found := false
-		for _, player := range l.Players {
-			if player == nil {
-				return []MatchDiff{}, fmt.Errorf("nil player found. not recording match")
-			}
+		result.Player.ELO = result.ELOBefore + result.ELOChange
+		result.Player.ELOChange = result.ELOChange
 
-			if result.Player == nil {
-				return []MatchDiff{}, fmt.Errorf("nil player found. not recording match")
-			}
+		matchDiff = append(matchDiff, MatchDiff{
+			Player: result.Player,
+			Diff:   result.ELOChange,
+		})
+	}
 
-			if player.Name == result.Player.Name {
-				result.Player.ELO = player.ELO
-				populatedResults = append(populatedResults, result)
-				found = true
-				break
-			}
+	match := Match{
+		ID:      l.nextMatchID(),
+		Results: results,
+		Date:    date,
+	}
+
+	// update the players' ELOs and stats
+	achievements := make([][]Achievement, len(results))
+	previousPeaks := make([]int, len(results))
+	for i, result := range results {
+		previousPeaks[i] = players[i].Stats.PeakELO
+		achievements[i] = l.applyResult(players[i], result, &match)
+		players[i].Achievements = append(players[i].Achievements, achievements[i]...)
+	}
+
+	// create the event
+	err = l.createEvent(match)
+	if err != nil {
+		return []MatchDiff{}, err
+	}
+
+	logged := make([]LogResult, 0, len(results))
+	for i, result := range results {
+		logged = append(logged, LogResult{Player: players[i].Name, Position: result.Position})
+	}
+	l.record(LogEntry{Op: OpAddMatch, Time: date, Results: logged})
+
+	l.emit(Event{Type: EventMatchRecorded, Match: &match, Diffs: matchDiff})
+	for i, player := range players {
+		l.emitRatingChanged(player, changes[i])
+		if player.ELO > previousPeaks[i] {
+			l.emit(Event{Type: EventPeakReached, Player: player, ELO: player.ELO, Diff: changes[i]})
 		}
+	}
 
-		if !found {
-			return []MatchDiff{}, fmt.Errorf("player %q not found. not recording match", result.Player.Name)
+	// announce any achievements now the match is on record
+	if l.OnAchievement != nil {
+		for i, player := range players {
+			for _, achievement := range achievements[i] {
+				l.OnAchievement(player, achievement)
+			}
 		}
 	}
 
-	// calculate the ELO changes
-	n := len(populatedResults)
-	kValue := 32 / (n - 1)
+	return matchDiff, nil
+}
 
-	// loop over every result
-	for player, result := range populatedResults {
-		curELO := result.Player.ELO
-		curPosition := result.Position
+// PreviewMatch returns the rating changes AddMatch would make for results
+// without recording the match or changing any player. The diffs refer to the
+// league's players, whose ratings are still those going into the match.
+func (l *League) PreviewMatch(results []*MatchResult) ([]MatchDiff, error) {
+	if len(l.Players) == 0 {
+		return nil, ErrNoPlayers
+	}
 
-		// loop over every other result
-		for opponentPlayer, opponentResult := range populatedResults {
-			// skip comparing the player to themselves
-			if player == opponentPlayer {
-				continue
-			}
+	players, err := l.resolveResults(results)
+	if err != nil {
+		return []MatchDiff{}, err
+	}
 
-			opponentELO := opponentResult.Player.ELO
-			opponentPosition := opponentResult.Position
+	// score a copy of the results so the caller's are left untouched
+	now := time.Now()
+	scratch := make([]*MatchResult, 0, len(results))
+	for i, result := range results {
+		scratch = append(scratch, &MatchResult{
+			Position:    result.Position,
+			Player:      players[i],
+			ELOBefore:   l.ratingAt(players[i], now),
+			Provisional: l.IsProvisional(players[i]),
+		})
+	}
 
-			// calculate the expected score
-			var S float64
+	changes := l.calculateChanges(scratch)
+	matchDiff := make([]MatchDiff, 0, len(results))
+	for i, player := range players {
+		matchDiff = append(matchDiff, MatchDiff{
+			Player: player,
+			Diff:   changes[i],
+		})
+	}
 
-			// if the player finished higher than the other player
-			if curPosition < opponentPosition {
-				S = 1.0
-			} else if curPosition == opponentPosition {
-				S = 0.5
-			} else {
-				S = 0.0
-			}
+	return matchDiff, nil
+}
+
+// UndoLastMatch removes the most recent match and restores every player in it
+// to the state they were in beforehand. It returns the rating changes that
+// were reverted.
+func (l *League) UndoLastMatch() ([]MatchDiff, error) {
+	if len(l.Matches) == 0 {
+		return nil, ErrMatchNotFound
+	}
 
-			// calculate the expected score
-			E := 1.0 / (1.0 + math.Pow(10, float64(opponentELO-curELO)/400))
+	last := l.Matches[len(l.Matches)-1]
+	l.Matches = l.Matches[:len(l.Matches)-1]
 
-			// update the player's ELO change
-			result.Player.ELOChange += int(math.Round(float64(kValue) * (S - E)))
+	matchDiff := make([]MatchDiff, 0, len(last.Results))
+	for _, result := range last.Results {
+		player, err := l.GetPlayer(result.Player.Name)
+		if err != nil {
+			// the player has since been removed, so there is nothing to restore
+			continue
 		}
 
-		// update the player's ELO
-		result.Player.ELO += result.Player.ELOChange
+		elo := player.ELO
+		player.Achievements = withoutMatches(player.Achievements, []Match{last})
+		l.rebuildPlayer(player)
 		matchDiff = append(matchDiff, MatchDiff{
-			Player: result.Player,
-			Diff:   result.Player.ELOChange,
+			Player: player,
+			Diff:   result.ELOChange,
 		})
+
+		l.emitRatingChanged(player, player.ELO-elo)
 	}
 
-	// update the players' ELOs
-	for _, result := range populatedResults {
+	l.record(LogEntry{Op: OpUndoMatch})
+
+	return matchDiff, nil
+}
+
+// rebuildPlayer resets a player and re-applies each of their recorded
+// results in order, leaving them exactly as the match history says they
+// should be.
+func (l *League) rebuildPlayer(player *Player) {
+	resetPlayer(player)
+
+	for _, match := range l.Matches {
+		for _, result := range match.Results {
+			if result.Player != nil && result.Player.Name == player.Name {
+				l.applyResult(player, result, &match)
+			}
+		}
+	}
+}
+
+// resolveResults returns the registered player for each result, in the same
+// order, or an error if the results can't be recorded as a match.
+func (l *League) resolveResults(results []*MatchResult) ([]*Player, error) {
+	if len(results) < 2 {
+		return nil, ErrInvalidMatch
+	}
+
+	players := make([]*Player, 0, len(results))
+	for _, result := range results {
+		if result == nil || result.Player == nil {
+			return nil, fmt.Errorf("nil player found. not recording match")
+		}
+
+		var found *Player
 		for _, player := range l.Players {
+			if player == nil {
+				return nil, fmt.Errorf("nil player found. not recording match")
+			}
+
 			if player.Name == result.Player.Name {
-				player.ELO = result.Player.ELO
-				player.Stats.MatchesPlayed++
-				if result.Position == 1 {
-					player.Stats.MatchesWon++
-				}
-
-				player.Stats.AllTimeAveragePlace += float64(result.Position)
-
-				player.Stats.Last5Finish = append(player.Stats.Last5Finish, result.Position)
-				if len(player.Stats.Last5Finish) > 5 {
-					player.Stats.Last5Finish = player.Stats.Last5Finish[1:]
-				}
-
-				if player.ELO > player.Stats.PeakELO {
-					player.Stats.PeakELO = player.ELO
-				}
+				found = player
 				break
 			}
 		}
+
+		if found == nil {
+			return nil, fmt.Errorf("player %q not found. not recording match: %w", result.Player.Name, ErrPlayerNotFound)
+		}
+
+		if found.Retired {
+			return nil, fmt.Errorf("player %q is retired. not recording match: %w", found.Name, ErrPlayerRetired)
+		}
+
+		for _, player := range players {
+			if player == found {
+				return nil, fmt.Errorf("player %q appears twice: %w", found.Name, ErrInvalidMatch)
+			}
+		}
+
+		players = append(players, found)
 	}
 
-	// create the event
-	err := l.createEvent(populatedResults)
-	if err != nil {
-		return []MatchDiff{}, err
+	return players, nil
+}
+
+// calculateChanges returns the rating change for each result.
+func (l *League) calculateChanges(results []*MatchResult) []int {
+	changes := make([]int, len(results))
+	for i, exchanges := range l.pairChanges(results) {
+		for _, change := range exchanges {
+			changes[i] += change
+		}
 	}
 
-	return matchDiff, nil
+	return changes
 }
 
-func (l *League) createEvent(results []*MatchResult) error {
-	event := Match{
-		Results: results,
-		Date:    time.Now(),
+// rateResults fills in ELOChange and Exchanges for each result, and returns
+// the changes.
+func (l *League) rateResults(results []*MatchResult) []int {
+	changes := make([]int, len(results))
+	pairs := l.pairChanges(results)
+
+	for i, result := range results {
+		result.Exchanges = map[string]int{}
+		for j, change := range pairs[i] {
+			if i != j {
+				result.Exchanges[results[j].Player.Name] = change
+				changes[i] += change
+			}
+		}
+		result.ELOChange = changes[i]
+	}
+
+	return changes
+}
+
+// pairChanges returns the rating each result gains (or loses) from every
+// other result, so pairChanges(results)[i][j] is what results[i] took from
+// results[j]. Results are scored in the order given, and each pairing uses
+// the opponent's rating as it stands at that point, so an opponent scored
+// earlier in the match counts with their rating after it.
+func (l *League) pairChanges(results []*MatchResult) [][]int {
+	pairs := make([][]int, len(results))
+	changes := make([]int, len(results))
+
+	// loop over every result
+	for i, result := range results {
+		pairs[i] = make([]int, len(results))
+
+		// loop over every other result
+		for j, opponentResult := range results {
+			// skip comparing the player to themselves
+			if i == j {
+				continue
+			}
+
+			opponentELO := opponentResult.ELOBefore
+			if j < i {
+				opponentELO += changes[j]
+			}
+
+			pairs[i][j] = l.resultChange(len(results), result, opponentResult, opponentELO)
+			changes[i] += pairs[i][j]
+		}
+	}
+
+	return pairs
+}
+
+// applyResult updates a player's rating and stats with a recorded result from
+// match, and returns any achievements the result earned them. It is up to
+// the caller to store those on the player.
+func (l *League) applyResult(player *Player, result *MatchResult, match *Match) []Achievement {
+	previousPeak := player.Stats.PeakELO
+
+	player.ELO = result.ELOBefore + result.ELOChange
+	player.ELOChange = result.ELOChange
+	player.Stats.MatchesPlayed++
+	if result.Position == 1 {
+		player.Stats.MatchesWon++
 	}
 
+	player.Stats.AllTimeAveragePlace += float64(result.Position)
+
+	player.Stats.recordForm(result.Position, len(match.Results), l.Config.formWindow())
+
+	if player.ELO > player.Stats.PeakELO {
+		player.Stats.PeakELO = player.ELO
+	}
+
+	return l.detectAchievements(player, result, match, previousPeak)
+}
+
+// kFactor is the K value each pairing in a match of n players is scored
+// with, so that the total at stake doesn't grow with the size of the field.
+func (l *League) kFactor(n int) int {
+	return l.Config.kFactor() / (n - 1)
+}
+
+// actualScore is the score a player earns against a single opponent: 1 for
+// finishing ahead of them, 0.5 for a tie and 0 for finishing behind.
+func actualScore(position, opponentPosition int) float64 {
+	if position < opponentPosition {
+		return 1.0
+	} else if position == opponentPosition {
+		return 0.5
+	}
+
+	return 0.0
+}
+
+// expectedScore is the standard Elo expectation of a player rated elo
+// against an opponent rated opponentELO.
+func expectedScore(elo, opponentELO int) float64 {
+	return 1.0 / (1.0 + math.Pow(10, float64(opponentELO-elo)/400))
+}
+
+// pairwiseChange is the rating a player gains (or loses) from a single
+// opponent in a match with the given K value.
+func pairwiseChange(kValue float64, elo, opponentELO, position, opponentPosition int) int {
+	S := actualScore(position, opponentPosition)
+	E := expectedScore(elo, opponentELO)
+
+	return int(math.Round(kValue * (S - E)))
+}
+
+// resultChange is the rating a result gains (or loses) from a single opponent
+// rated opponentELO in a match of n players, taking provisional ratings into
+// account.
+func (l *League) resultChange(n int, result, opponent *MatchResult, opponentELO int) int {
+	kValue := float64(l.kFactor(n)) *
+		l.Config.Provisional.kMultiplier(result.Provisional) *
+		l.Config.Provisional.opponentWeight(opponent.Provisional)
+
+	return pairwiseChange(kValue, result.ELOBefore, opponentELO, result.Position, opponent.Position)
+}
+
+func (l *League) createEvent(event Match) error {
 	l.Matches = append(l.Matches, event)
 
 	return nil
 }
 
+// nextMatchID returns the ID the next recorded match should have.
+func (l *League) nextMatchID() int {
+	id := 0
+	for _, season := range l.Seasons {
+		for _, match := range season.Matches {
+			id = max(id, match.ID)
+		}
+	}
+
+	for _, match := range l.Matches {
+		id = max(id, match.ID)
+	}
+
+	return id + 1
+}
+
 func (l *League) AddPlayer(name string) error {
+	return l.AddPlayerWithRating(name, l.Config.initialELO())
+}
+
+// AddPlayerWithRating adds a player who starts on the given rating instead of
+// the league's default, such as a strong player arriving from another league.
+// Resets and replays put them back on this rating rather than the default.
+// Ratings below 1 are rejected with ErrInvalidPlayer.
+func (l *League) AddPlayerWithRating(name string, elo int) error {
+	if elo < 1 {
+		return fmt.Errorf("rating %d must be at least 1: %w", elo, ErrInvalidPlayer)
+	}
+
 	name = strings.ToLower(name)
 
 	for _, p := range l.Players {
@@ -214,17 +645,12 @@ func (l *League) AddPlayer(name string) error {
 		}
 	}
 
-	l.Players = append(l.Players, &Player{
-		Name: name,
-		ELO:  InitialELO,
-		Stats: &PlayerStats{
-			Last5Finish:         []int{},
-			MatchesPlayed:       0,
-			MatchesWon:          0,
-			AllTimeAveragePlace: 0,
-			PeakELO:             InitialELO,
-		},
-	})
+	player := &Player{Name: name, StartingELO: elo}
+	resetPlayer(player)
+	l.assignStyle(player)
+	l.Players = append(l.Players, player)
+	l.emit(Event{Type: EventPlayerAdded, Player: player})
+	l.record(LogEntry{Op: OpAddPlayer, Player: name, ELO: elo})
 
 	return nil
 }
@@ -241,41 +667,229 @@ func (l *League) GetPlayer(name string) (*Player, error) {
 	return nil, ErrPlayerNotFound
 }
 
+// RemovePlayer retires a player. They stay in the league's history, so past
+// matches and graphs are unaffected, but they drop off the leaderboard and
+// can't enter new matches until restored with RestorePlayer. Use PurgePlayer
+// to delete a player and their results outright.
 func (l *League) RemovePlayer(name string) error {
-	name = strings.ToLower(name)
+	p, err := l.GetPlayer(name)
+	if err != nil {
+		return err
+	}
 
-	for i, p := range l.Players {
-		if strings.ToLower(p.Name) == name {
+	p.Retired = true
+	l.emit(Event{Type: EventPlayerRemoved, Player: p})
+	l.record(LogEntry{Op: OpRemovePlayer, Player: p.Name})
+	return nil
+}
+
+// RestorePlayer brings a retired player back into the league with the rating
+// and stats they retired with.
+func (l *League) RestorePlayer(name string) error {
+	p, err := l.GetPlayer(name)
+	if err != nil {
+		return err
+	}
+
+	p.Retired = false
+	l.emit(Event{Type: EventPlayerAdded, Player: p})
+	l.record(LogEntry{Op: OpRestorePlayer, Player: p.Name})
+	return nil
+}
+
+// PurgePlayer deletes a player and every one of their results, then re-rates
+// the league as if they had never played. Matches left with fewer than two
+// players are dropped, and the remaining finishers move up to fill the gap.
+func (l *League) PurgePlayer(name string) error {
+	p, err := l.GetPlayer(name)
+	if err != nil {
+		return err
+	}
+
+	for i, player := range l.Players {
+		if player == p {
 			l.Players = append(l.Players[:i], l.Players[i+1:]...)
-			return nil
+			break
 		}
 	}
+	l.emit(Event{Type: EventPlayerRemoved, Player: p})
+
+	matches := make([]Match, 0, len(l.Matches))
+	for _, match := range l.Matches {
+		results := make([]*MatchResult, 0, len(match.Results))
+		for _, result := range match.Results {
+			if result.Player != nil && result.Player.Name != p.Name {
+				results = append(results, result)
+			}
+		}
+
+		if len(results) < 2 {
+			continue
+		}
+
+		match.Results = compactPositions(results)
+		matches = append(matches, match)
+	}
+	l.Matches = matches
+
+	if err := l.recalculate(); err != nil {
+		return err
+	}
 
-	return ErrPlayerNotFound
+	l.record(LogEntry{Op: OpPurgePlayer, Player: p.Name})
+	return nil
 }
 
-func (l *League) ResetPlayers() {
+// compactPositions renumbers results so positions run from 1 with no gaps,
+// keeping ties tied.
+func compactPositions(results []*MatchResult) []*MatchResult {
+	positions := make([]int, 0, len(results))
+	for _, result := range results {
+		positions = append(positions, result.Position)
+	}
+	sort.Ints(positions)
+
+	for _, result := range results {
+		// a result's new position is one more than the number of distinct
+		// positions ahead of it
+		position := 1
+		for i, other := range positions {
+			if other >= result.Position {
+				break
+			}
+			if i == 0 || other != positions[i-1] {
+				position++
+			}
+		}
+
+		result.Position = position
+	}
+
+	return results
+}
+
+// Recalculate re-rates every player from scratch by replaying the league's
+// matches in order. Use it after editing match history by hand.
+func (l *League) Recalculate() error {
+	if err := l.recalculate(); err != nil {
+		return err
+	}
+
+	l.record(LogEntry{Op: OpRecalculate})
+	return nil
+}
+
+func (l *League) recalculate() error {
+	// achievements from this season are worked out again as it's replayed
 	for _, p := range l.Players {
-		p.ELO = InitialELO
-		p.ELOChange = 0
-		p.Stats = &PlayerStats{
-			Last5Finish:         []int{},
-			MatchesPlayed:       0,
-			MatchesWon:          0,
-			AllTimeAveragePlace: 0,
-			PeakELO:             InitialELO,
+		p.Achievements = withoutMatches(p.Achievements, l.Matches)
+		resetPlayer(p)
+	}
+
+	lastPlayed := map[*Player]time.Time{}
+	for m := range l.Matches {
+		match := &l.Matches[m]
+		players := make([]*Player, 0, len(match.Results))
+		for _, result := range match.Results {
+			if result.Player == nil {
+				return fmt.Errorf("nil player found. not recalculating")
+			}
+
+			player := l.findPlayer(result.Player.Name)
+			if player == nil {
+				return fmt.Errorf("player %q not found. not recalculating", result.Player.Name)
+			}
+
+			// decay from the player's last match up to this one
+			if last, ok := lastPlayed[player]; ok {
+				player.ELO = l.Config.Decay.apply(player.ELO, match.Date.Sub(last))
+			}
+			lastPlayed[player] = match.Date
+
+			players = append(players, player)
+			result.ELOBefore = player.ELO
+			result.Provisional = l.IsProvisional(player)
+		}
+
+		l.rateResults(match.Results)
+		for i, result := range match.Results {
+			players[i].Achievements = append(players[i].Achievements, l.applyResult(players[i], result, match)...)
 		}
 	}
+
+	l.emit(Event{Type: EventLeagueReset})
+	return nil
+}
+
+// findPlayer returns the registered player with exactly the given name, or
+// nil if there isn't one.
+func (l *League) findPlayer(name string) *Player {
+	for _, p := range l.Players {
+		if p.Name == name {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func (l *League) ResetPlayers() {
+	for _, p := range l.Players {
+		resetPlayer(p)
+	}
+	l.emit(Event{Type: EventLeagueReset})
+	l.record(LogEntry{Op: OpResetPlayers})
+}
+
+// resetPlayer puts a player back to their starting rating with empty stats.
+func resetPlayer(p *Player) {
+	p.ELO = p.StartingELO
+	p.ELOChange = 0
+	p.Stats = &PlayerStats{
+		Last5Finish:         []int{},
+		Last5FieldSize:      []int{},
+		MatchesPlayed:       0,
+		MatchesWon:          0,
+		AllTimeAveragePlace: 0,
+		PeakELO:             p.StartingELO,
+	}
 }
 
 func (l *League) ResetMatches() {
 	l.Matches = []Match{}
+	l.emit(Event{Type: EventLeagueReset})
+	l.record(LogEntry{Op: OpResetMatches})
 }
 
 func (l *League) GetPlayers() []*Player {
 	return l.Players
 }
 
+// Leaderboard returns the active players ordered from highest to lowest ELO.
+// Retired players are left out. If the league has a decay policy it is
+// applied first, and idle players are left out too if the policy hides them.
+func (l *League) Leaderboard() []*Player {
+	return l.leaderboard(time.Now())
+}
+
+// leaderboard returns the leaderboard as it stands at now.
+func (l *League) leaderboard(now time.Time) []*Player {
+	l.ApplyDecay(now)
+
+	players := make([]*Player, 0, len(l.Players))
+	for _, p := range l.Players {
+		if !p.Retired && !l.hidden(p, now) {
+			players = append(players, p)
+		}
+	}
+
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].ELO > players[j].ELO
+	})
+
+	return players
+}
+
 func (l *League) GetMatches() []Match {
 	return l.Matches
 }
@@ -298,20 +912,102 @@ func (l *League) GetPlayerELO(name string) (int, error) {
 	return p.ELO, nil
 }
 
-func (l *League) GenerateGraph() (string, error) {
+// graphWidth and graphHeight are the size ELO graphs are drawn at.
+const (
+	graphWidth  = 30 * vg.Centimeter
+	graphHeight = 20 * vg.Centimeter
+)
+
+// GenerateGraph draws every player's rating over time and saves it as elo.svg
+// and elo.png in the working directory, returning the PNG's path. Options
+// such as GraphTop or GraphByDate change what is drawn.
+func (l *League) GenerateGraph(opts ...GraphOption) (string, error) {
+	p, err := l.graph(newGraphOptions(opts))
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.Save(graphWidth, graphHeight, "elo.svg"); err != nil {
+		return "", err
+	}
+
+	if err := p.Save(graphWidth, graphHeight, "elo.png"); err != nil {
+		return "", err
+	}
+
+	return "elo.png", nil
+}
+
+// WriteGraph draws the same graph as GenerateGraph to w in the given format,
+// such as "png" or "svg".
+func (l *League) WriteGraph(w io.Writer, format string, opts ...GraphOption) error {
+	p, err := l.graph(newGraphOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	writer, err := p.WriterTo(graphWidth, graphHeight, format)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.WriteTo(w)
+	return err
+}
+
+// graph plots players' ratings over time.
+func (l *League) graph(o graphOptions) (*plot.Plot, error) {
 	if len(l.Players) == 0 {
-		return "", ErrNoPlayers
+		return nil, ErrNoPlayers
 	}
 
-	// sort the players by ELO
-	for i := 0; i < len(l.Players); i++ {
-		for j := i + 1; j < len(l.Players); j++ {
-			if l.Players[i].ELO < l.Players[j].ELO {
-				l.Players[i], l.Players[j] = l.Players[j], l.Players[i]
-			}
+	matches, restricted, err := l.graphMatches(o)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := l.graphPlayers(o, matches, restricted)
+	if err != nil {
+		return nil, err
+	}
+
+	styles, err := l.playerStyles(players...)
+	if err != nil {
+		return nil, err
+	}
+
+	// a line for each player, with their rating at the end of it
+	type line struct {
+		player *Player
+		rating int
+		xys    plotter.XYs
+		labels []string
+	}
+
+	lines := []line{}
+	for _, player := range players {
+		xys, labels, raced := l.ratingLine(o, matches, player)
+		if !restricted {
+			lines = append(lines, line{player: player, rating: player.ELO, xys: xys, labels: labels})
+		} else if raced {
+			// players who didn't race in the period are left out
+			lines = append(lines, line{player: player, rating: int(xys[len(xys)-1].Y), xys: xys, labels: labels})
 		}
 	}
 
+	if len(lines) == 0 {
+		return nil, ErrNoPlayers
+	}
+
+	// sort the players by ELO, highest first
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].rating > lines[j].rating
+	})
+
+	if o.top > 0 && len(lines) > o.top {
+		lines = lines[:o.top]
+	}
+
 	p := plot.New()
 	p.Title.Text = "ELO over time"
 	p.X.Label.Text = "Races"
@@ -320,106 +1016,61 @@ func (l *League) GenerateGraph() (string, error) {
 	// add a grid
 	p.Add(plotter.NewGrid())
 
-	// tick every 5 x values
+	// tick every 3 races, or by day
 	p.X.Tick.Marker = RaceTicker{}
 	p.Y.Tick.Marker = ELOTicker{}
+	if o.byDate {
+		p.X.Label.Text = "Date"
+		p.X.Tick.Marker = plot.TimeTicks{Format: "2 Jan"}
+	} else {
+		p.X.Min = 0
+	}
 
 	// pad the y axis a bit
-	p.Y.Min = float64(l.Players[len(l.Players)-1].ELO - 50)
-	p.Y.Max = float64(l.Players[0].ELO + 50)
-
-	// pad the x axis a bit
-	p.X.Min = 0
-
-	for j, player := range l.Players {
-		xys := make(plotter.XYs, len(l.Matches)+1)
-		labels := make([]string, len(l.Matches)+1)
-
-		var firstRaceIndex int = -1
-
-		// loop over every match
-		for i, event := range l.Matches {
-
-			// loop over every result
-			for _, result := range event.Results {
-
-				// if the result is for the player we're plotting
-				if result.Player != nil && result.Player.Name == player.Name {
-
-					// and this is the first time we've seen them
-					if firstRaceIndex < 0 {
-						// add the initial ELO to the race before their first
-						xys[i].X = float64(i)
-						xys[i].Y = float64(InitialELO)
-						labels[i] = strconv.Itoa(InitialELO)
-						firstRaceIndex = i
-					}
-
-					// add the ELO for the player for the current race
-					xys[i+1].X = float64(i + 1)
-					xys[i+1].Y = float64(result.Player.ELO)
-					break
-				} else {
-					// if we haven't seen the player yet, just copy the last value
-					xys[i+1].X = float64(i)
-					xys[i+1].Y = xys[i].Y
-				}
-			}
-
-			// add an ELO label every 3 races
-			if i%3 == 0 {
-				labels[i+1] = strconv.FormatFloat(xys[i+1].Y, 'f', 0, 64)
-			}
-		}
-
-		if firstRaceIndex < 0 {
-			// set the last value to the initial ELO
-			xys[len(xys)-1].X = float64(len(xys) - 1)
-			xys[len(xys)-1].Y = float64(InitialELO)
-			labels[len(labels)-1] = strconv.Itoa(InitialELO)
-			firstRaceIndex = len(xys) - 1
+	p.Y.Min, p.Y.Max = math.Inf(1), math.Inf(-1)
+	for _, line := range lines {
+		for _, xy := range line.xys {
+			p.Y.Min = math.Min(p.Y.Min, xy.Y-50)
+			p.Y.Max = math.Max(p.Y.Max, xy.Y+50)
 		}
+	}
 
-		// add the last label
-		labels[len(labels)-1] = strconv.FormatFloat(xys[len(xys)-1].Y, 'f', 0, 64)
-
+	for _, line := range lines {
 		// create a line for the driver
-		line, points, err := plotter.NewLinePoints(xys[firstRaceIndex:])
+		plotLine, points, err := plotter.NewLinePoints(line.xys)
 		if err != nil {
-			return "", err
-		}
-
-		// create labels for the line
-		label, err := plotter.NewLabels(plotter.XYLabels{
-			XYs:    xys[firstRaceIndex:],
-			Labels: labels[firstRaceIndex:],
-		})
-		if err != nil {
-			return "", err
+			return nil, err
 		}
 
 		// style the line and points
-		line.Color = colors[j%len(colors)]
-		points.Shape = draw.CircleGlyph{}
-		points.Color = colors[j%len(colors)]
-		line.StepStyle = plotter.NoStep
-
-		// add the line and labels to the plot
-		p.Add(line, points, label)
-
-		// add the driver to the legend
-		p.Legend.Add(fmt.Sprintf("%s (%d)", player.Name, player.ELO), line)
-	}
-
-	if err := p.Save(30*vg.Centimeter, 20*vg.Centimeter, "elo.svg"); err != nil {
-		return "", err
-	}
+		style := styles[line.player]
+		plotLine.Color = style.color
+		plotLine.Dashes = style.dashes
+		points.Shape = style.glyph
+		points.Color = style.color
+		plotLine.StepStyle = plotter.NoStep
+
+		// add the line to the plot
+		p.Add(plotLine, points)
+
+		if o.labelEvery > 0 {
+			label, err := plotter.NewLabels(plotter.XYLabels{XYs: line.xys, Labels: line.labels})
+			if err != nil {
+				return nil, err
+			}
+			p.Add(label)
+		}
 
-	if err := p.Save(30*vg.Centimeter, 20*vg.Centimeter, "elo.png"); err != nil {
-		return "", err
+		// add the driver to the legend, with their rating at the end of the
+		// graph if it doesn't run to the present
+		rating := l.FormatELO(line.player)
+		if restricted {
+			rating = strconv.Itoa(line.rating)
+		}
+		p.Legend.Add(fmt.Sprintf("%s (%s)", line.player.Name, rating), plotLine, points)
 	}
 
-	return "elo.png", nil
+	return p, nil
 }
 
 type RaceTicker struct{}
@@ -82,6 +82,59 @@ func TestPlayer_AddPlayer(t *testing.T) {
 	})
 }
 
+func TestPlayer_AddPlayerWithRating(t *testing.T) {
+	t.Run("AddPlayerWithRating", func(t *testing.T) {
+		l := newTestLeague(t, "player1", "player2")
+		assert.NoError(t, l.AddPlayerWithRating("player3", 1200))
+		assert.Equal(t, multielo.ErrPlayerAlreadyExists, l.AddPlayerWithRating("Player3", 900))
+		assert.ErrorIs(t, l.AddPlayerWithRating("player4", 0), multielo.ErrInvalidPlayer)
+		assert.ErrorIs(t, l.AddPlayerWithRating("player4", -5), multielo.ErrInvalidPlayer)
+		assert.Len(t, l.Players, 3)
+
+		player3, err := l.GetPlayer("player3")
+		assert.NoError(t, err)
+		assert.Equal(t, 1200, player3.ELO)
+		assert.Equal(t, 1200, player3.StartingELO)
+		assert.Equal(t, 1200, player3.Stats.PeakELO)
+
+		recordMatch(t, l, "player1", "player3", "player2")
+		assert.Equal(t, 1200, l.Matches[0].Results[1].ELOBefore)
+
+		l.ResetPlayers()
+		assert.Equal(t, 1200, player3.ELO)
+		assert.NoError(t, l.Recalculate())
+
+		_, err = l.UndoLastMatch()
+		assert.NoError(t, err)
+		assert.Equal(t, 1200, player3.ELO)
+
+		_, err = l.GenerateGraph()
+		assert.NoError(t, err)
+	})
+
+	t.Run("InitialELO", func(t *testing.T) {
+		l := multielo.NewLeague(multielo.WithInitialELO(1500))
+		assert.Equal(t, 1500, l.Config.InitialELO)
+
+		assert.NoError(t, l.AddPlayer("player1"))
+		assert.NoError(t, l.AddPlayer("player2"))
+		recordMatch(t, l, "player1", "player2")
+
+		player1, _ := l.GetPlayer("player1")
+		assert.Equal(t, 1516, player1.ELO)
+
+		_, err := l.CloseSeason("season1", 0.5)
+		assert.NoError(t, err)
+		assert.Equal(t, 1508, player1.ELO)
+	})
+
+	t.Run("InitialELOUnset", func(t *testing.T) {
+		l := &multielo.League{}
+		assert.NoError(t, l.AddPlayer("player1"))
+		assert.Equal(t, multielo.InitialELO, l.Players[0].ELO)
+	})
+}
+
 func TestPlayer_GetPlayer(t *testing.T) {
 	t.Run("GetPlayer", func(t *testing.T) {
 		l := multielo.NewLeague()
@@ -153,6 +206,109 @@ func TestPlayer_RemovePlayer(t *testing.T) {
 			t.Error(err)
 		}
 	})
+
+	t.Run("RemovePlayerKeepsHistory", func(t *testing.T) {
+		l := newTestLeague(t, "player1", "player2", "player3")
+		recordMatch(t, l, "player1", "player2", "player3")
+		recordMatch(t, l, "player3", "player2")
+
+		assert.NoError(t, l.RemovePlayer("player1"))
+
+		player1, err := l.GetPlayer("player1")
+		assert.NoError(t, err)
+		assert.True(t, player1.Retired)
+		assert.Len(t, l.Matches, 2)
+
+		for _, p := range l.Leaderboard() {
+			assert.NotEqual(t, "player1", p.Name)
+		}
+
+		player2, _ := l.GetPlayer("player2")
+		_, err = l.AddMatch([]*multielo.MatchResult{
+			{Player: player1, Position: 1},
+			{Player: player2, Position: 2},
+		})
+		assert.ErrorIs(t, err, multielo.ErrPlayerRetired)
+
+		assert.Equal(t, multielo.ErrPlayerAlreadyExists, l.AddPlayer("player1"))
+
+		_, err = l.GenerateGraph()
+		assert.NoError(t, err)
+
+		assert.NoError(t, l.RestorePlayer("player1"))
+		assert.Len(t, l.Leaderboard(), 3)
+		recordMatch(t, l, "player1", "player2")
+	})
+}
+
+func TestPlayer_PurgePlayer(t *testing.T) {
+	t.Run("PurgePlayer", func(t *testing.T) {
+		l := newTestLeague(t, "player1", "player2", "player3")
+		recordMatch(t, l, "player1", "player2", "player3")
+		recordMatch(t, l, "player1", "player3")
+		recordMatch(t, l, "player3", "player2")
+
+		assert.NoError(t, l.PurgePlayer("player1"))
+
+		_, err := l.GetPlayer("player1")
+		assert.Equal(t, multielo.ErrPlayerNotFound, err)
+		assert.Len(t, l.Players, 2)
+
+		// the second match only had one other player left in it
+		assert.Len(t, l.Matches, 2)
+		assert.Equal(t, 1, l.Matches[0].Results[0].Position)
+		assert.Equal(t, 2, l.Matches[0].Results[1].Position)
+
+		// the league should be rated as if player1 had never played
+		expected := newTestLeague(t, "player2", "player3")
+		recordMatch(t, expected, "player2", "player3")
+		recordMatch(t, expected, "player3", "player2")
+
+		for _, name := range []string{"player2", "player3"} {
+			got, _ := l.GetPlayer(name)
+			want, _ := expected.GetPlayer(name)
+			assert.Equal(t, want.ELO, got.ELO, name)
+			assert.Equal(t, want.Stats, got.Stats, name)
+		}
+	})
+
+	t.Run("PurgePlayerNotFound", func(t *testing.T) {
+		l := newTestLeague(t, "player1")
+		assert.Equal(t, multielo.ErrPlayerNotFound, l.PurgePlayer("player2"))
+	})
+}
+
+func TestLeague_Recalculate(t *testing.T) {
+	l := newTestLeague(t, "player1", "player2", "player3")
+	recordMatch(t, l, "player1", "player2", "player3")
+	recordMatch(t, l, "player2", "player1", "player3")
+
+	ratings := map[string]int{}
+	for _, p := range l.Players {
+		ratings[p.Name] = p.ELO
+	}
+
+	l.ResetPlayers()
+	assert.NoError(t, l.Recalculate())
+
+	for _, p := range l.Players {
+		assert.Equal(t, ratings[p.Name], p.ELO, p.Name)
+		assert.Equal(t, 2, p.Stats.MatchesPlayed)
+	}
+}
+
+func TestLeague_Leaderboard(t *testing.T) {
+	l := newTestLeague(t, "player1", "player2", "player3")
+	recordMatch(t, l, "player3", "player1", "player2")
+
+	leaderboard := l.Leaderboard()
+	assert.Len(t, leaderboard, 3)
+	assert.Equal(t, "player3", leaderboard[0].Name)
+	assert.Equal(t, "player1", leaderboard[1].Name)
+	assert.Equal(t, "player2", leaderboard[2].Name)
+
+	// the league's own order is left alone
+	assert.Equal(t, "player1", l.Players[0].Name)
 }
 
 func TestPlayer_ResetPlayers(t *testing.T) {
@@ -279,6 +435,21 @@ func TestMatch_AddMatch(t *testing.T) {
 
 	})
 
+	t.Run("AddMatchFourPlayers", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "carol")
+		assert.NoError(t, l.AddPlayerWithRating("bob", 1100))
+		assert.NoError(t, l.AddPlayerWithRating("dave", 900))
+
+		// each player is scored against the ratings of those ahead of them
+		// in the results after this match, and those behind them before it
+		diff := recordMatch(t, l, "carol", "dave", "alice", "bob")
+		assert.Equal(t, []int{15, 11, -5, -19}, diffs(diff))
+
+		diff, err := l.AddMatchPlaces([][]string{{"bob"}, {"alice", "dave"}, {"carol"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{11, 0, 4, -15}, diffs(diff))
+	})
+
 	t.Run("AddMatchPlayerNotFound", func(t *testing.T) {
 		l := multielo.NewLeague()
 		err := l.AddPlayer("player1")
@@ -328,6 +499,214 @@ func TestMatch_AddMatch(t *testing.T) {
 	})
 }
 
+// diffs returns just the rating changes from a match.
+func diffs(diff []multielo.MatchDiff) []int {
+	changes := make([]int, len(diff))
+	for i, d := range diff {
+		changes[i] = d.Diff
+	}
+
+	return changes
+}
+
+func TestMatch_AddMatchByName(t *testing.T) {
+	t.Run("AddMatchByName", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol")
+
+		diff, err := l.AddMatchByName("Alice", "bob", "carol")
+		assert.NoError(t, err)
+		assert.Len(t, diff, 3)
+
+		alice, err := l.GetPlayer("alice")
+		assert.NoError(t, err)
+		assert.Equal(t, alice, diff[0].Player)
+		assert.Equal(t, alice, l.Matches[0].Results[0].Player)
+		assert.Equal(t, multielo.InitialELO+diff[0].Diff, alice.ELO)
+		assert.Equal(t, 3, l.Matches[0].Results[2].Position)
+	})
+
+	t.Run("AddMatchPlaces", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol")
+
+		diff, err := l.AddMatchPlaces([][]string{{"alice"}, {"bob", "carol"}})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, l.Matches[0].Results[1].Position)
+		assert.Equal(t, 2, l.Matches[0].Results[2].Position)
+		assert.Equal(t, diff[1].Diff, diff[2].Diff)
+		assert.Equal(t, 16, diff[0].Diff)
+
+		_, err = l.AddMatchPlaces([][]string{{"alice"}, {}, {"bob"}})
+		assert.ErrorIs(t, err, multielo.ErrInvalidMatch)
+
+		_, err = l.AddMatchByName("alice", "mallory")
+		assert.ErrorIs(t, err, multielo.ErrPlayerNotFound)
+		assert.Len(t, l.Matches, 1)
+	})
+
+	t.Run("AddMatchDoesNotMutate", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+
+		alice := &multielo.Player{Name: "alice"}
+		results := []*multielo.MatchResult{
+			{Player: alice, Position: 1},
+			{Player: &multielo.Player{Name: "bob"}, Position: 2},
+		}
+
+		diff, err := l.AddMatch(results)
+		assert.NoError(t, err)
+		assert.Equal(t, 16, diff[0].Diff)
+
+		assert.Equal(t, multielo.Player{Name: "alice"}, *alice)
+		assert.Equal(t, multielo.MatchResult{Player: alice, Position: 1}, *results[0])
+		assert.NotSame(t, results[0], l.Matches[0].Results[0])
+
+		player, err := l.GetPlayer("alice")
+		assert.NoError(t, err)
+		assert.Same(t, player, l.Matches[0].Results[0].Player)
+		assert.Equal(t, multielo.InitialELO+16, player.ELO)
+	})
+}
+
+func TestMatch_PreviewMatch(t *testing.T) {
+	t.Run("PreviewMatchesAddMatch", func(t *testing.T) {
+		l := newTestLeague(t, "player1", "player2", "player3")
+		recordMatch(t, l, "player3", "player1", "player2")
+
+		results := func() []*multielo.MatchResult {
+			return []*multielo.MatchResult{
+				{Player: &multielo.Player{Name: "player1"}, Position: 1},
+				{Player: &multielo.Player{Name: "player2"}, Position: 2},
+				{Player: &multielo.Player{Name: "player3"}, Position: 2},
+			}
+		}
+
+		preview, err := l.PreviewMatch(results())
+		assert.NoError(t, err)
+		assert.Len(t, preview, 3)
+		assert.Len(t, l.Matches, 1)
+
+		player1, err := l.GetPlayer("player1")
+		assert.NoError(t, err)
+		assert.Equal(t, player1, preview[0].Player)
+		elo := player1.ELO
+		played := player1.Stats.MatchesPlayed
+
+		diff, err := l.AddMatch(results())
+		assert.NoError(t, err)
+		for i := range diff {
+			assert.Equal(t, diff[i].Diff, preview[i].Diff)
+		}
+
+		assert.Equal(t, elo+preview[0].Diff, player1.ELO)
+		assert.Equal(t, played+1, player1.Stats.MatchesPlayed)
+	})
+
+	t.Run("PreviewMatchDoesNotMutate", func(t *testing.T) {
+		l := newTestLeague(t, "player1", "player2")
+		player1, err := l.GetPlayer("player1")
+		assert.NoError(t, err)
+		player2, err := l.GetPlayer("player2")
+		assert.NoError(t, err)
+
+		results := []*multielo.MatchResult{
+			{Player: player1, Position: 1},
+			{Player: player2, Position: 2},
+		}
+
+		preview, err := l.PreviewMatch(results)
+		assert.NoError(t, err)
+		assert.Equal(t, 16, preview[0].Diff)
+		assert.Equal(t, -15, preview[1].Diff)
+
+		assert.Equal(t, multielo.InitialELO, player1.ELO)
+		assert.Equal(t, 0, player1.ELOChange)
+		assert.Equal(t, 0, player1.Stats.MatchesPlayed)
+		assert.Equal(t, 0, results[0].ELOChange)
+		assert.Empty(t, l.Matches)
+	})
+
+	t.Run("PreviewMatchInvalid", func(t *testing.T) {
+		l := newTestLeague(t, "player1")
+		player1, err := l.GetPlayer("player1")
+		assert.NoError(t, err)
+
+		_, err = l.PreviewMatch([]*multielo.MatchResult{{Player: player1, Position: 1}})
+		assert.Equal(t, multielo.ErrInvalidMatch, err)
+
+		_, err = l.PreviewMatch([]*multielo.MatchResult{
+			{Player: player1, Position: 1},
+			{Player: player1, Position: 2},
+		})
+		assert.ErrorIs(t, err, multielo.ErrInvalidMatch)
+
+		_, err = multielo.NewLeague().PreviewMatch(nil)
+		assert.Equal(t, multielo.ErrNoPlayers, err)
+	})
+}
+
+func TestMatch_UndoLastMatch(t *testing.T) {
+	t.Run("UndoLastMatch", func(t *testing.T) {
+		l := newTestLeague(t, "player1", "player2", "player3")
+		orders := [][]string{
+			{"player1", "player2", "player3"},
+			{"player2", "player3", "player1"},
+			{"player3", "player1", "player2"},
+		}
+		for i := 0; i < 7; i++ {
+			recordMatch(t, l, orders[i%len(orders)]...)
+		}
+		recordMatch(t, l, "player2", "player3")
+
+		// snapshot every player before the match we'll undo
+		before := map[string]multielo.Player{}
+		for _, p := range l.Players {
+			stats := *p.Stats
+			stats.Last5Finish = append([]int{}, p.Stats.Last5Finish...)
+			snapshot := *p
+			snapshot.Stats = &stats
+			before[p.Name] = snapshot
+		}
+
+		diff := recordMatch(t, l, "player3", "player1", "player2")
+		assert.Len(t, l.Matches, 9)
+
+		reverted, err := l.UndoLastMatch()
+		assert.NoError(t, err)
+		assert.Len(t, l.Matches, 8)
+		assert.Len(t, reverted, 3)
+
+		for i := range diff {
+			assert.Equal(t, diff[i].Player.Name, reverted[i].Player.Name)
+			assert.Equal(t, diff[i].Diff, reverted[i].Diff)
+		}
+
+		for _, p := range l.Players {
+			assert.Equal(t, before[p.Name], *p, p.Name)
+		}
+	})
+
+	t.Run("UndoLastMatchEmpty", func(t *testing.T) {
+		l := newTestLeague(t, "player1")
+		_, err := l.UndoLastMatch()
+		assert.Equal(t, multielo.ErrMatchNotFound, err)
+	})
+
+	t.Run("UndoOnlyMatch", func(t *testing.T) {
+		l := newTestLeague(t, "player1", "player2")
+		recordMatch(t, l, "player1", "player2")
+
+		_, err := l.UndoLastMatch()
+		assert.NoError(t, err)
+
+		for _, p := range l.Players {
+			assert.Equal(t, multielo.InitialELO, p.ELO)
+			assert.Equal(t, multielo.InitialELO, p.Stats.PeakELO)
+			assert.Equal(t, 0, p.Stats.MatchesPlayed)
+			assert.Empty(t, p.Stats.Last5Finish)
+		}
+	})
+}
+
 func testTicker(t *testing.T, ticker plot.Ticker, start, end float64, expected int) {
 	ticks := ticker.Ticks(start, end)
 	if len(ticks) != expected {
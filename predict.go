@@ -0,0 +1,100 @@
+package multielo
+
+import (
+	"math"
+	"time"
+)
+
+// Prediction is the forecast for one player in an upcoming match.
+type Prediction struct {
+	Player *Player
+
+	// ExpectedScore is the sum of the player's expected score against each
+	// opponent, the same quantity AddMatch measures the actual result
+	// against. It ranges from 0 to the number of opponents.
+	ExpectedScore float64
+	// WinProbability is the chance the player finishes first.
+	WinProbability float64
+	// ExpectedPosition is the player's mean finishing position, where 1 is
+	// first.
+	ExpectedPosition float64
+}
+
+// Predict forecasts a match between the named players using their current
+// ratings, after any decay the league's policy would apply. Predictions are
+// returned in the order the players were given.
+//
+// Finishing orders are modelled analytically: each player's strength is
+// 10^(ELO/400), and the chance of finishing ahead of a given opponent matches
+// the expected score AddMatch uses, so win probabilities sum to one and a
+// two-player prediction agrees exactly with the Elo expectation.
+func (l *League) Predict(names []string) ([]Prediction, error) {
+	if len(names) < 2 {
+		return nil, ErrInvalidMatch
+	}
+
+	players := make([]*Player, 0, len(names))
+	for _, name := range names {
+		player, err := l.GetPlayer(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if player.Retired {
+			return nil, ErrPlayerRetired
+		}
+
+		for _, p := range players {
+			if p == player {
+				return nil, ErrInvalidMatch
+			}
+		}
+
+		players = append(players, player)
+	}
+
+	// rate everyone as AddMatch would, with any decay applied
+	now := time.Now()
+	ratings := make([]int, len(players))
+	for i, player := range players {
+		ratings[i] = l.ratingAt(player, now)
+	}
+
+	// normalise strengths against the top rating to keep them finite
+	maxELO := ratings[0]
+	for _, rating := range ratings {
+		if rating > maxELO {
+			maxELO = rating
+		}
+	}
+
+	var totalStrength float64
+	strengths := make([]float64, len(players))
+	for i, rating := range ratings {
+		strengths[i] = math.Pow(10, float64(rating-maxELO)/400)
+		totalStrength += strengths[i]
+	}
+
+	predictions := make([]Prediction, len(players))
+	for i, player := range players {
+		prediction := Prediction{
+			Player:           player,
+			WinProbability:   strengths[i] / totalStrength,
+			ExpectedPosition: 1,
+		}
+
+		for j := range players {
+			if i == j {
+				continue
+			}
+
+			E := expectedScore(ratings[i], ratings[j])
+			prediction.ExpectedScore += E
+			prediction.ExpectedPosition += 1 - E
+		}
+
+		predictions[i] = prediction
+	}
+
+	return predictions, nil
+}
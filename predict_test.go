@@ -0,0 +1,71 @@
+package multielo_test
+
+import (
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeague_Predict(t *testing.T) {
+	t.Run("PredictEvenField", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol", "dave")
+
+		predictions, err := l.Predict([]string{"alice", "bob", "carol", "dave"})
+		assert.NoError(t, err)
+		assert.Len(t, predictions, 4)
+
+		for _, prediction := range predictions {
+			assert.InDelta(t, 1.5, prediction.ExpectedScore, 1e-9)
+			assert.InDelta(t, 0.25, prediction.WinProbability, 1e-9)
+			assert.InDelta(t, 2.5, prediction.ExpectedPosition, 1e-9)
+		}
+	})
+
+	t.Run("PredictFavourite", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol")
+		for i := 0; i < 5; i++ {
+			recordMatch(t, l, "alice", "bob", "carol")
+		}
+
+		predictions, err := l.Predict([]string{"carol", "alice", "bob"})
+		assert.NoError(t, err)
+		assert.Equal(t, "carol", predictions[0].Player.Name)
+		assert.Equal(t, "alice", predictions[1].Player.Name)
+
+		var totalProbability, totalPosition float64
+		for _, prediction := range predictions {
+			totalProbability += prediction.WinProbability
+			totalPosition += prediction.ExpectedPosition
+		}
+		assert.InDelta(t, 1.0, totalProbability, 1e-9)
+		assert.InDelta(t, 6.0, totalPosition, 1e-9)
+
+		assert.Greater(t, predictions[1].WinProbability, predictions[2].WinProbability)
+		assert.Greater(t, predictions[2].WinProbability, predictions[0].WinProbability)
+		assert.Less(t, predictions[1].ExpectedPosition, predictions[2].ExpectedPosition)
+	})
+
+	t.Run("PredictTwoPlayersMatchesExpectedScore", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		recordMatch(t, l, "alice", "bob")
+
+		predictions, err := l.Predict([]string{"alice", "bob"})
+		assert.NoError(t, err)
+		assert.InDelta(t, predictions[0].ExpectedScore, predictions[0].WinProbability, 1e-9)
+		assert.InDelta(t, 2-predictions[0].ExpectedScore, predictions[0].ExpectedPosition, 1e-9)
+	})
+
+	t.Run("PredictInvalid", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+
+		_, err := l.Predict([]string{"alice"})
+		assert.Equal(t, multielo.ErrInvalidMatch, err)
+
+		_, err = l.Predict([]string{"alice", "Alice"})
+		assert.Equal(t, multielo.ErrInvalidMatch, err)
+
+		_, err = l.Predict([]string{"alice", "carol"})
+		assert.Equal(t, multielo.ErrPlayerNotFound, err)
+	})
+}
@@ -0,0 +1,64 @@
+package multielo
+
+import (
+	"strconv"
+)
+
+// ProvisionalPolicy treats a newcomer's rating as unsettled for their first
+// few matches. While provisional, their rating moves faster so it can find
+// its level, and their opponents' ratings move less, since a loss to an
+// unknown quantity says little about them.
+type ProvisionalPolicy struct {
+	// Matches is how many matches a player's rating stays provisional for,
+	// counted across every season.
+	Matches int `json:"matches" yaml:"matches"`
+
+	// KMultiplier scales the K value of provisional players.
+	KMultiplier float64 `json:"k_multiplier" yaml:"k_multiplier"`
+	// OpponentWeight scales the K value of anyone facing a provisional
+	// player, and is usually below 1. Either factor left at zero is 1,
+	// leaving the K value alone.
+	OpponentWeight float64 `json:"opponent_weight" yaml:"opponent_weight"`
+}
+
+// IsProvisional reports whether a player's rating is still provisional. It is
+// always false if the league has no provisional policy.
+func (l *League) IsProvisional(p *Player) bool {
+	if l.Config.Provisional == nil {
+		return false
+	}
+
+	played := l.archivedMatches(p) + p.Stats.MatchesPlayed
+	return played < l.Config.Provisional.Matches
+}
+
+// FormatELO returns a player's rating for display, with a trailing "?" if it
+// is still provisional.
+func (l *League) FormatELO(p *Player) string {
+	elo := strconv.Itoa(p.ELO)
+	if l.IsProvisional(p) {
+		elo += "?"
+	}
+
+	return elo
+}
+
+// kMultiplier is the factor a player's own K value is scaled by. A nil
+// policy leaves it alone.
+func (p *ProvisionalPolicy) kMultiplier(provisional bool) float64 {
+	if p == nil || !provisional || p.KMultiplier == 0 {
+		return 1
+	}
+
+	return p.KMultiplier
+}
+
+// opponentWeight is the factor a player's K value is scaled by when facing an
+// opponent. A nil policy leaves it alone.
+func (p *ProvisionalPolicy) opponentWeight(provisional bool) float64 {
+	if p == nil || !provisional || p.OpponentWeight == 0 {
+		return 1
+	}
+
+	return p.OpponentWeight
+}
@@ -0,0 +1,103 @@
+package multielo_test
+
+import (
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+func newProvisionalLeague(t *testing.T) *multielo.League {
+	t.Helper()
+
+	// alice and bob are established before the policy is switched on
+	l := newTestLeague(t, "alice", "bob")
+	recordMatch(t, l, "alice", "bob")
+	recordMatch(t, l, "bob", "alice")
+
+	assert.NoError(t, l.AddPlayer("carol"))
+	l.Config.Provisional = &multielo.ProvisionalPolicy{
+		Matches:        2,
+		KMultiplier:    2,
+		OpponentWeight: 0.5,
+	}
+
+	return l
+}
+
+func TestLeague_Provisional(t *testing.T) {
+	t.Run("ProvisionalRatingsMoveFaster", func(t *testing.T) {
+		l := newProvisionalLeague(t)
+		alice, _ := l.GetPlayer("alice")
+		carol, _ := l.GetPlayer("carol")
+
+		assert.False(t, l.IsProvisional(alice))
+		assert.True(t, l.IsProvisional(carol))
+		assert.Equal(t, "1000?", l.FormatELO(carol))
+
+		diff := recordMatch(t, l, "carol", "alice")
+		assert.True(t, l.Matches[2].Results[0].Provisional)
+		assert.False(t, l.Matches[2].Results[1].Provisional)
+
+		// carol's K is doubled while alice's is halved
+		assert.Equal(t, []int{32, -7}, diffs(diff))
+
+		h2h, err := l.HeadToHead("carol", "alice")
+		assert.NoError(t, err)
+		assert.Equal(t, diff[0].Diff, h2h.RatingExchanged)
+
+		recordMatch(t, l, "carol", "bob")
+		assert.False(t, l.IsProvisional(carol))
+		assert.Equal(t, 2, carol.Stats.MatchesPlayed)
+		assert.NotContains(t, l.FormatELO(carol), "?")
+	})
+
+	t.Run("ProvisionalAcrossSeasons", func(t *testing.T) {
+		l := newProvisionalLeague(t)
+		carol, _ := l.GetPlayer("carol")
+
+		recordMatch(t, l, "carol", "alice")
+		_, err := l.CloseSeason("january", 1)
+		assert.NoError(t, err)
+		assert.True(t, l.IsProvisional(carol))
+
+		recordMatch(t, l, "carol", "alice")
+		assert.False(t, l.IsProvisional(carol))
+	})
+
+	t.Run("ProvisionalReplay", func(t *testing.T) {
+		l := newProvisionalLeague(t)
+		recordMatch(t, l, "carol", "alice", "bob")
+		recordMatch(t, l, "bob", "carol")
+
+		ratings := map[string]int{}
+		for _, p := range l.Players {
+			ratings[p.Name] = p.ELO
+		}
+
+		l.ResetPlayers()
+		assert.NoError(t, l.Recalculate())
+		for _, p := range l.Players {
+			assert.Equal(t, ratings[p.Name], p.ELO, p.Name)
+		}
+	})
+	t.Run("PartialPolicy", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		recordMatch(t, l, "alice", "bob")
+		recordMatch(t, l, "bob", "alice")
+
+		// factors left at zero leave the K value alone
+		l.Config.Provisional = &multielo.ProvisionalPolicy{Matches: 2}
+		assert.NoError(t, l.AddPlayer("carol"))
+
+		diff := recordMatch(t, l, "carol", "alice")
+		assert.True(t, l.Matches[2].Results[0].Provisional)
+		assert.NotZero(t, diff[0].Diff)
+		assert.InDelta(t, -diff[1].Diff, diff[0].Diff, 1)
+
+		l.Config.Provisional.KMultiplier = 2
+		diff = recordMatch(t, l, "bob", "carol")
+		assert.NotZero(t, diff[0].Diff)
+		assert.InDelta(t, -2*diff[0].Diff, diff[1].Diff, 2)
+	})
+}
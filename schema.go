@@ -0,0 +1,641 @@
+package multielo
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SchemaVersion is the version of the JSON format League.MarshalJSON writes.
+//
+// Version 2 stores every player once, in "players", and refers to them
+// everywhere else by their "id":
+//
+//	{
+//	  "version": 2,
+//	  "config": {"k_factor": 32, ...},
+//	  "season_start": "2024-01-01T00:00:00Z",
+//	  "players": [
+//	    {"id": 1, "name": "alice", "elo": 1016, "elo_change": 16, "starting_elo": 1000, "style": 1,
+//	     "stats": {"matches_played": 1, ...}, "achievements": [...]},
+//	    {"id": 2, "name": "bob", ...}
+//	  ],
+//	  "matches": [
+//	    {"id": 1, "date": "2024-01-02T00:00:00Z", "results": [
+//	      {"player": 1, "position": 1, "elo_before": 1000, "elo_change": 16, "exchanges": {"2": 16}},
+//	      {"player": 2, "position": 2, "elo_before": 1000, "elo_change": -15, "exchanges": {"1": -15}}
+//	    ]}
+//	  ],
+//	  "seasons": [
+//	    {"name": "january", "start": ..., "end": ..., "standings": [{"rank": 1, "player": 1, "elo": 1016, "stats": {...}}],
+//	     "matches": [...]}
+//	  ],
+//	  "log": [...]
+//	}
+//
+// Players deleted with PurgePlayer who still appear in closed seasons are
+// kept in "players" with "purged": true. A player's "style" numbers them in
+// the order they joined, which picks how graphs draw them.
+//
+// Version 1 is the format encoding/json produced for a League before it
+// had a schema, with every player copied into each of their results. It is
+// still read, and players are matched up by name.
+const SchemaVersion = 2
+
+type leagueJSON struct {
+	Version     int          `json:"version"`
+	Config      Config       `json:"config"`
+	SeasonStart time.Time    `json:"season_start"`
+	Players     []playerJSON `json:"players"`
+	Matches     []matchJSON  `json:"matches"`
+	Seasons     []seasonJSON `json:"seasons"`
+	Log         []LogEntry   `json:"log,omitempty"`
+}
+
+type playerJSON struct {
+	ID           int               `json:"id"`
+	Name         string            `json:"name"`
+	ELO          int               `json:"elo"`
+	ELOChange    int               `json:"elo_change"`
+	StartingELO  int               `json:"starting_elo"`
+	Retired      bool              `json:"retired,omitempty"`
+	Purged       bool              `json:"purged,omitempty"`
+	Style        int               `json:"style,omitempty"`
+	Stats        statsJSON         `json:"stats"`
+	Achievements []achievementJSON `json:"achievements,omitempty"`
+}
+
+type statsJSON struct {
+	MatchesPlayed         int     `json:"matches_played"`
+	MatchesWon            int     `json:"matches_won"`
+	AllTimeAveragePlace   float64 `json:"all_time_average_place"`
+	LastFinishes          []int   `json:"last_finishes"`
+	LastFieldSizes        []int   `json:"last_field_sizes"`
+	PeakELO               int     `json:"peak_elo"`
+	FormScore             float64 `json:"form_score"`
+	WeightedAverageFinish float64 `json:"weighted_average_finish"`
+	WinStreak             int     `json:"win_streak"`
+	LongestWinStreak      int     `json:"longest_win_streak"`
+	PodiumStreak          int     `json:"podium_streak"`
+	LongestPodiumStreak   int     `json:"longest_podium_streak"`
+}
+
+type achievementJSON struct {
+	Kind        AchievementKind `json:"kind"`
+	MatchID     int             `json:"match"`
+	Date        time.Time       `json:"date"`
+	Description string          `json:"description"`
+}
+
+type matchJSON struct {
+	ID      int          `json:"id"`
+	Date    time.Time    `json:"date"`
+	Results []resultJSON `json:"results"`
+}
+
+type resultJSON struct {
+	Player      int         `json:"player"`
+	Position    int         `json:"position"`
+	ELOBefore   int         `json:"elo_before"`
+	ELOChange   int         `json:"elo_change"`
+	Provisional bool        `json:"provisional,omitempty"`
+	Exchanges   map[int]int `json:"exchanges,omitempty"`
+}
+
+type seasonJSON struct {
+	Name      string         `json:"name"`
+	Start     time.Time      `json:"start"`
+	End       time.Time      `json:"end"`
+	Standings []standingJSON `json:"standings"`
+	Matches   []matchJSON    `json:"matches"`
+}
+
+type standingJSON struct {
+	Rank   int       `json:"rank"`
+	Player int       `json:"player"`
+	ELO    int       `json:"elo"`
+	Stats  statsJSON `json:"stats"`
+}
+
+// leagueV1 is the version 1 format: League as encoding/json saw it before it
+// had JSON tags.
+type leagueV1 struct {
+	Players     []*Player
+	Matches     []Match
+	Config      Config
+	Seasons     []Season
+	SeasonStart time.Time
+}
+
+// MarshalJSON encodes the league in the current schema, described at
+// SchemaVersion. It has a value receiver so a League held by value is
+// encoded the same way.
+func (l League) MarshalJSON() ([]byte, error) {
+	ids := map[*Player]int{}
+	out := leagueJSON{
+		Version:     SchemaVersion,
+		Config:      l.Config,
+		SeasonStart: l.SeasonStart,
+		Players:     []playerJSON{},
+		Matches:     []matchJSON{},
+		Seasons:     []seasonJSON{},
+		Log:         l.Log(),
+	}
+
+	addPlayer := func(p *Player, purged bool) {
+		if _, ok := ids[p]; ok {
+			return
+		}
+
+		ids[p] = len(out.Players) + 1
+		player := playerJSON{
+			ID:          ids[p],
+			Name:        p.Name,
+			ELO:         p.ELO,
+			ELOChange:   p.ELOChange,
+			StartingELO: p.StartingELO,
+			Retired:     p.Retired,
+			Purged:      purged,
+			Style:       p.style,
+			Stats:       toStatsJSON(p.Stats),
+		}
+		for _, achievement := range p.Achievements {
+			player.Achievements = append(player.Achievements, achievementJSON(achievement))
+		}
+		out.Players = append(out.Players, player)
+	}
+
+	for _, p := range l.Players {
+		addPlayer(p, false)
+	}
+
+	// closed seasons can still refer to purged players
+	for _, season := range l.Seasons {
+		for _, match := range season.Matches {
+			for _, result := range match.Results {
+				if result.Player != nil {
+					addPlayer(result.Player, true)
+				}
+			}
+		}
+	}
+
+	matches := func(matches []Match) []matchJSON {
+		out := []matchJSON{}
+		for _, match := range matches {
+			m := matchJSON{ID: match.ID, Date: match.Date, Results: []resultJSON{}}
+			for _, result := range match.Results {
+				if result.Player == nil {
+					continue
+				}
+
+				r := resultJSON{
+					Player:      ids[result.Player],
+					Position:    result.Position,
+					ELOBefore:   result.ELOBefore,
+					ELOChange:   result.ELOChange,
+					Provisional: result.Provisional,
+				}
+				for _, opponent := range match.Results {
+					if opponent.Player == nil {
+						continue
+					}
+
+					if change, ok := result.Exchanges[opponent.Player.Name]; ok {
+						if r.Exchanges == nil {
+							r.Exchanges = map[int]int{}
+						}
+						r.Exchanges[ids[opponent.Player]] = change
+					}
+				}
+				m.Results = append(m.Results, r)
+			}
+			out = append(out, m)
+		}
+
+		return out
+	}
+
+	out.Matches = matches(l.Matches)
+	for _, season := range l.Seasons {
+		s := seasonJSON{
+			Name:      season.Name,
+			Start:     season.Start,
+			End:       season.End,
+			Standings: []standingJSON{},
+			Matches:   matches(season.Matches),
+		}
+
+		for _, standing := range season.Standings {
+			p := seasonPlayer(&l, season, standing.Name)
+			if p == nil {
+				// a purged player who never finished a match that season
+				p = &Player{Name: standing.Name, Stats: &PlayerStats{}}
+				addPlayer(p, true)
+			}
+
+			s.Standings = append(s.Standings, standingJSON{
+				Rank:   standing.Rank,
+				Player: ids[p],
+				ELO:    standing.ELO,
+				Stats:  toStatsJSON(&standing.Stats),
+			})
+		}
+		out.Seasons = append(out.Seasons, s)
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a league written in any schema version, migrating
+// older versions to the current one. Subscribers and OnAchievement are left
+// as they are.
+func (l *League) UnmarshalJSON(data []byte) error {
+	var header struct {
+		Version *int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLeague, err)
+	}
+
+	var decoded *League
+	var err error
+	switch {
+	case header.Version == nil:
+		decoded, err = migrateV1(data)
+	case *header.Version == SchemaVersion:
+		decoded, err = decodeV2(data)
+	default:
+		err = fmt.Errorf("%w: unsupported schema version %d", ErrInvalidLeague, *header.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := decoded.Config.Validate(); err != nil {
+		return err
+	}
+
+	l.Players = decoded.Players
+	l.Matches = decoded.Matches
+	l.Config = decoded.Config
+	l.Seasons = decoded.Seasons
+	l.SeasonStart = decoded.SeasonStart
+	l.log = decoded.log
+	l.archived = nil
+	l.styles = decoded.styles
+	return nil
+}
+
+// decodeV2 decodes the current schema.
+func decodeV2(data []byte) (*League, error) {
+	var in leagueJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLeague, err)
+	}
+
+	l := &League{
+		Players:     []*Player{},
+		Matches:     []Match{},
+		Config:      in.Config,
+		Seasons:     []Season{},
+		SeasonStart: in.SeasonStart,
+		log:         in.Log,
+	}
+
+	players := map[int]*Player{}
+	names := map[string]bool{}
+	for _, player := range in.Players {
+		if _, ok := players[player.ID]; ok {
+			return nil, fmt.Errorf("%w: player id %d used twice", ErrInvalidLeague, player.ID)
+		}
+
+		p := &Player{
+			Name:        player.Name,
+			ELO:         player.ELO,
+			ELOChange:   player.ELOChange,
+			StartingELO: player.StartingELO,
+			Retired:     player.Retired,
+			Stats:       player.Stats.stats(),
+			style:       player.Style,
+		}
+		l.styles = max(l.styles, player.Style)
+		for _, achievement := range player.Achievements {
+			p.Achievements = append(p.Achievements, Achievement(achievement))
+		}
+		players[player.ID] = p
+
+		if !player.Purged {
+			if names[p.Name] {
+				return nil, fmt.Errorf("%w: player %q appears twice", ErrInvalidLeague, p.Name)
+			}
+			names[p.Name] = true
+			l.Players = append(l.Players, p)
+		}
+	}
+
+	// files from before styles were saved number players in their order
+	for _, player := range in.Players {
+		l.assignStyle(players[player.ID])
+	}
+
+	matches := func(in []matchJSON) ([]Match, error) {
+		matches := []Match{}
+		for _, match := range in {
+			m := Match{ID: match.ID, Date: match.Date, Results: []*MatchResult{}}
+			for _, result := range match.Results {
+				p, ok := players[result.Player]
+				if !ok {
+					return nil, fmt.Errorf("%w: match %d refers to unknown player %d", ErrInvalidLeague, match.ID, result.Player)
+				}
+
+				r := &MatchResult{
+					Position:    result.Position,
+					Player:      p,
+					ELOBefore:   result.ELOBefore,
+					ELOChange:   result.ELOChange,
+					Provisional: result.Provisional,
+				}
+				for id, change := range result.Exchanges {
+					opponent, ok := players[id]
+					if !ok {
+						return nil, fmt.Errorf("%w: match %d refers to unknown player %d", ErrInvalidLeague, match.ID, id)
+					}
+					if r.Exchanges == nil {
+						r.Exchanges = map[string]int{}
+					}
+					r.Exchanges[opponent.Name] = change
+				}
+				m.Results = append(m.Results, r)
+			}
+			matches = append(matches, m)
+		}
+
+		return matches, nil
+	}
+
+	var err error
+	if l.Matches, err = matches(in.Matches); err != nil {
+		return nil, err
+	}
+
+	for _, season := range in.Seasons {
+		s := Season{
+			Name:      season.Name,
+			Start:     season.Start,
+			End:       season.End,
+			Standings: []Standing{},
+		}
+
+		if s.Matches, err = matches(season.Matches); err != nil {
+			return nil, err
+		}
+
+		for _, standing := range season.Standings {
+			p, ok := players[standing.Player]
+			if !ok {
+				return nil, fmt.Errorf("%w: season %q refers to unknown player %d", ErrInvalidLeague, season.Name, standing.Player)
+			}
+
+			s.Standings = append(s.Standings, Standing{
+				Rank:  standing.Rank,
+				Name:  p.Name,
+				ELO:   standing.ELO,
+				Stats: *standing.Stats.stats(),
+			})
+		}
+		l.Seasons = append(l.Seasons, s)
+	}
+
+	return l, nil
+}
+
+// migrateV1 decodes the version 1 format, where every result holds its own
+// copy of the player, and points results back at a single player each.
+// Players who only appear in results, because they were deleted, are added
+// back as retired players.
+//
+// Files saved before ratings were recorded against each result have their
+// matches re-rated to fill them in. The players keep the ratings stored in
+// the file, though, which can differ from the re-rated ones if the league's
+// config has changed since or a rating was set by hand.
+//
+// Version 1 files had no log, so the migrated league's log starts with the
+// league as it was read, in an OpImport entry after OpCreateLeague.
+func migrateV1(data []byte) (*League, error) {
+	var in leagueV1
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLeague, err)
+	}
+
+	l := &League{
+		Players:     []*Player{},
+		Matches:     []Match{},
+		Config:      in.Config,
+		Seasons:     []Season{},
+		SeasonStart: in.SeasonStart,
+	}
+
+	byName := map[string]*Player{}
+	addPlayer := func(p *Player) {
+		if p.Stats == nil {
+			p.Stats = &PlayerStats{PeakELO: p.ELO}
+		}
+		if p.StartingELO == 0 {
+			p.StartingELO = l.Config.initialELO()
+		}
+		if p.ELO == 0 {
+			p.ELO = p.StartingELO
+		}
+		l.assignStyle(p)
+
+		byName[p.Name] = p
+		l.Players = append(l.Players, p)
+	}
+
+	for _, p := range in.Players {
+		if p == nil {
+			continue
+		}
+
+		if byName[p.Name] != nil {
+			return nil, fmt.Errorf("%w: player %q appears twice", ErrInvalidLeague, p.Name)
+		}
+
+		addPlayer(p)
+	}
+
+	resolve := func(matches []Match) []Match {
+		for i := range matches {
+			for _, result := range matches[i].Results {
+				if result == nil || result.Player == nil {
+					continue
+				}
+
+				p, ok := byName[result.Player.Name]
+				if !ok {
+					p = result.Player
+					p.Retired = true
+					addPlayer(p)
+				}
+				result.Player = p
+			}
+		}
+
+		return matches
+	}
+
+	if in.Matches != nil {
+		l.Matches = resolve(in.Matches)
+	}
+
+	for _, season := range in.Seasons {
+		season.Matches = resolve(season.Matches)
+		l.Seasons = append(l.Seasons, season)
+	}
+
+	// version 1 matches had no IDs, so number them from the first season on,
+	// as achievements and undos tell matches apart by ID
+	id := 0
+	for s := range l.Seasons {
+		for i := range l.Seasons[s].Matches {
+			id++
+			l.Seasons[s].Matches[i].ID = id
+		}
+	}
+	for i := range l.Matches {
+		id++
+		l.Matches[i].ID = id
+	}
+
+	if unrated(l.Matches) {
+		stored := map[*Player][2]int{}
+		for _, p := range l.Players {
+			stored[p] = [2]int{p.ELO, p.ELOChange}
+		}
+
+		if err := l.recalculate(); err != nil {
+			return nil, err
+		}
+
+		for p, ratings := range stored {
+			p.ELO, p.ELOChange = ratings[0], ratings[1]
+			p.Stats.PeakELO = max(p.Stats.PeakELO, p.ELO)
+		}
+	}
+
+	snapshot, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+
+	config := l.Config
+	l.record(LogEntry{Op: OpCreateLeague, Time: l.SeasonStart, Config: &config})
+	l.record(LogEntry{Op: OpImport, Snapshot: snapshot})
+
+	return l, nil
+}
+
+// unrated reports whether any of the matches were saved before ratings were
+// recorded against each result.
+func unrated(matches []Match) bool {
+	for _, match := range matches {
+		for _, result := range match.Results {
+			if result != nil && result.ELOBefore == 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// importSnapshot replaces the league's players, matches, seasons and settings
+// with those in the snapshot of an OpImport entry, keeping its log.
+func (l *League) importSnapshot(snapshot json.RawMessage) error {
+	imported := &League{}
+	if err := json.Unmarshal(snapshot, imported); err != nil {
+		return err
+	}
+
+	l.Players = imported.Players
+	l.Matches = imported.Matches
+	l.Config = imported.Config
+	l.Seasons = imported.Seasons
+	l.SeasonStart = imported.SeasonStart
+	l.archived = nil
+	l.styles = imported.styles
+	l.record(LogEntry{Op: OpImport, Snapshot: snapshot})
+	return nil
+}
+
+// clone returns a copy of the league's players, matches, seasons, settings
+// and log that shares nothing with it. Subscribers and OnAchievement aren't
+// copied, so changing the clone is invisible outside it.
+func (l *League) clone() (*League, error) {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := &League{}
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
+
+// seasonPlayer finds the player with the given name in a season's matches,
+// or in the league if they didn't play that season.
+func seasonPlayer(l *League, season Season, name string) *Player {
+	for _, match := range season.Matches {
+		for _, result := range match.Results {
+			if result.Player != nil && result.Player.Name == name {
+				return result.Player
+			}
+		}
+	}
+
+	return l.findPlayer(name)
+}
+
+func toStatsJSON(s *PlayerStats) statsJSON {
+	if s == nil {
+		return statsJSON{LastFinishes: []int{}, LastFieldSizes: []int{}}
+	}
+
+	return statsJSON{
+		MatchesPlayed:         s.MatchesPlayed,
+		MatchesWon:            s.MatchesWon,
+		AllTimeAveragePlace:   s.AllTimeAveragePlace,
+		LastFinishes:          append([]int{}, s.Last5Finish...),
+		LastFieldSizes:        append([]int{}, s.Last5FieldSize...),
+		PeakELO:               s.PeakELO,
+		FormScore:             s.FormScore,
+		WeightedAverageFinish: s.WeightedAverageFinish,
+		WinStreak:             s.WinStreak,
+		LongestWinStreak:      s.LongestWinStreak,
+		PodiumStreak:          s.PodiumStreak,
+		LongestPodiumStreak:   s.LongestPodiumStreak,
+	}
+}
+
+func (s statsJSON) stats() *PlayerStats {
+	stats := &PlayerStats{
+		MatchesPlayed:         s.MatchesPlayed,
+		MatchesWon:            s.MatchesWon,
+		AllTimeAveragePlace:   s.AllTimeAveragePlace,
+		Last5Finish:           append([]int{}, s.LastFinishes...),
+		Last5FieldSize:        append([]int{}, s.LastFieldSizes...),
+		PeakELO:               s.PeakELO,
+		FormScore:             s.FormScore,
+		WeightedAverageFinish: s.WeightedAverageFinish,
+		WinStreak:             s.WinStreak,
+		LongestWinStreak:      s.LongestWinStreak,
+		PodiumStreak:          s.PodiumStreak,
+		LongestPodiumStreak:   s.LongestPodiumStreak,
+	}
+
+	return stats
+}
@@ -0,0 +1,282 @@
+package multielo_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTrip marshals a league and unmarshals it into a new one.
+func roundTrip(t *testing.T, l *multielo.League) (*multielo.League, []byte) {
+	t.Helper()
+
+	data, err := json.Marshal(l)
+	assert.NoError(t, err)
+
+	decoded := &multielo.League{}
+	assert.NoError(t, json.Unmarshal(data, decoded))
+
+	return decoded, data
+}
+
+func TestLeague_JSON(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		l := multielo.NewLeague(multielo.WithKFactor(40), multielo.WithProvisional(multielo.ProvisionalPolicy{Matches: 2, KMultiplier: 2}))
+		for _, name := range []string{"alice", "bob", "carol", "dave"} {
+			assert.NoError(t, l.AddPlayer(name))
+		}
+
+		recordMatch(t, l, "alice", "bob", "carol", "dave")
+		recordMatch(t, l, "dave", "carol")
+		_, err := l.CloseSeason("january", 0.5)
+		assert.NoError(t, err)
+		recordMatch(t, l, "bob", "alice", "carol")
+		assert.NoError(t, l.RemovePlayer("carol"))
+		assert.NoError(t, l.PurgePlayer("dave"))
+
+		decoded, data := roundTrip(t, l)
+
+		// encoding the decoded league gives the same JSON
+		again, err := json.Marshal(decoded)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(data), string(again))
+
+		assert.Equal(t, l.Config, decoded.Config)
+		assert.Len(t, decoded.Players, 3)
+		for i, p := range l.Players {
+			assert.Equal(t, p.Name, decoded.Players[i].Name)
+			assert.Equal(t, p.ELO, decoded.Players[i].ELO)
+			assert.Equal(t, p.Retired, decoded.Players[i].Retired)
+			assert.Equal(t, *p.Stats, *decoded.Players[i].Stats)
+		}
+
+		// results point at the league's players again
+		for _, result := range decoded.Matches[0].Results {
+			p, err := decoded.GetPlayer(result.Player.Name)
+			assert.NoError(t, err)
+			assert.Same(t, p, result.Player)
+		}
+
+		// dave is gone from the league but still in january's matches
+		_, err = decoded.GetPlayer("dave")
+		assert.ErrorIs(t, err, multielo.ErrPlayerNotFound)
+		january, err := decoded.GetSeason("january")
+		assert.NoError(t, err)
+		assert.Equal(t, "dave", january.Matches[1].Results[0].Player.Name)
+		assert.Same(t, january.Matches[0].Results[3].Player, january.Matches[1].Results[0].Player)
+		assert.Equal(t, l.Seasons[0].Standings, january.Standings)
+
+		// the decoded league carries on where the original left off
+		_, err = l.AddMatchByName("alice", "bob")
+		assert.NoError(t, err)
+		_, err = decoded.AddMatchByName("alice", "bob")
+		assert.NoError(t, err)
+		assert.Equal(t, l.Matches[1].ID, decoded.Matches[1].ID)
+		assert.Equal(t, l.Players[0].ELO, decoded.Players[0].ELO)
+
+		log := decoded.Log()
+		assert.Len(t, log, len(l.Log()))
+		assert.Equal(t, multielo.OpAddMatch, log[len(log)-1].Op)
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		recordMatch(t, l, "bob", "alice")
+
+		data, err := json.Marshal(l)
+		assert.NoError(t, err)
+
+		var schema struct {
+			Version int `json:"version"`
+			Players []struct {
+				ID   int    `json:"id"`
+				Name string `json:"name"`
+			} `json:"players"`
+			Matches []struct {
+				Results []struct {
+					Player int `json:"player"`
+				} `json:"results"`
+			} `json:"matches"`
+		}
+		assert.NoError(t, json.Unmarshal(data, &schema))
+		assert.Equal(t, multielo.SchemaVersion, schema.Version)
+		assert.Equal(t, "bob", schema.Players[1].Name)
+		assert.Equal(t, schema.Players[1].ID, schema.Matches[0].Results[0].Player)
+	})
+
+	t.Run("ByValue", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		l.OnAchievement = func(*multielo.Player, multielo.Achievement) {}
+		recordMatch(t, l, "alice", "bob")
+
+		byPointer, err := json.Marshal(l)
+		assert.NoError(t, err)
+
+		// a league held by value is still written in the schema
+		byValue, err := json.Marshal(*l)
+		assert.NoError(t, err)
+		assert.Equal(t, byPointer, byValue)
+
+		held, err := json.Marshal(struct{ League multielo.League }{*l})
+		assert.NoError(t, err)
+		assert.Contains(t, string(held), `"version":2`)
+	})
+
+	t.Run("MigrateV1", func(t *testing.T) {
+		// as encoding/json wrote a league before it had a schema, with no
+		// ratings recorded against each result
+		data := `{
+			"Players": [
+				{"Name": "alice", "ELO": 1016, "ELOChange": 16, "Stats": {"MatchesPlayed": 1, "MatchesWon": 1, "AllTimeAveragePlace": 1, "Last5Finish": [1], "PeakELO": 1016}},
+				{"Name": "bob", "ELO": 984, "ELOChange": -16, "Stats": {"MatchesPlayed": 1, "MatchesWon": 0, "AllTimeAveragePlace": 2, "Last5Finish": [2], "PeakELO": 1000}}
+			],
+			"Matches": [
+				{"Results": [
+					{"Position": 1, "Player": {"Name": "alice", "ELO": 1016, "ELOChange": 16}},
+					{"Position": 2, "Player": {"Name": "bob", "ELO": 984, "ELOChange": -16}}
+				], "Date": "2024-01-01T00:00:00Z"}
+			]
+		}`
+
+		l := &multielo.League{}
+		assert.NoError(t, json.Unmarshal([]byte(data), l))
+		assert.Len(t, l.Players, 2)
+
+		alice, err := l.GetPlayer("alice")
+		assert.NoError(t, err)
+		assert.Same(t, alice, l.Matches[0].Results[0].Player)
+		assert.Equal(t, 1016, alice.ELO)
+		assert.Equal(t, multielo.InitialELO, alice.StartingELO)
+		assert.Equal(t, multielo.InitialELO, l.Matches[0].Results[0].ELOBefore)
+		assert.Equal(t, 16, l.Matches[0].Results[0].ELOChange)
+
+		// and it is written back in the current schema
+		_, migrated := roundTrip(t, l)
+		var schema struct {
+			Version int `json:"version"`
+		}
+		assert.NoError(t, json.Unmarshal(migrated, &schema))
+		assert.Equal(t, multielo.SchemaVersion, schema.Version)
+
+		_, err = l.AddMatchByName("bob", "alice")
+		assert.NoError(t, err)
+		assert.Equal(t, 1016-17, alice.ELO)
+	})
+
+	t.Run("MigrateV1Undo", func(t *testing.T) {
+		data := `{
+			"Players": [{"Name": "alice", "ELO": 1031}, {"Name": "bob", "ELO": 969}],
+			"Matches": [
+				{"Results": [
+					{"Position": 1, "Player": {"Name": "alice"}},
+					{"Position": 2, "Player": {"Name": "bob"}}
+				], "Date": "2024-01-01T00:00:00Z"},
+				{"Results": [
+					{"Position": 1, "Player": {"Name": "alice"}},
+					{"Position": 2, "Player": {"Name": "bob"}}
+				], "Date": "2024-01-02T00:00:00Z"}
+			]
+		}`
+
+		l := &multielo.League{}
+		assert.NoError(t, json.Unmarshal([]byte(data), l))
+		assert.Equal(t, 1, l.Matches[0].ID)
+		assert.Equal(t, 2, l.Matches[1].ID)
+
+		// undoing the second match leaves the first win, from the first
+		_, err := l.UndoLastMatch()
+		assert.NoError(t, err)
+
+		alice, err := l.GetPlayer("alice")
+		assert.NoError(t, err)
+		assert.Len(t, alice.Achievements, 1)
+		assert.Equal(t, multielo.AchievementFirstWin, alice.Achievements[0].Kind)
+		assert.Equal(t, 1, alice.Achievements[0].MatchID)
+		assert.Equal(t, 1, alice.Stats.MatchesPlayed)
+
+		_, err = l.AddMatchByName("bob", "alice")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, l.Matches[1].ID)
+	})
+
+	t.Run("MigrateV1Deleted", func(t *testing.T) {
+		// carol was deleted but still appears in a result, and alice's rating
+		// was set by hand after the match
+		data := `{
+			"Players": [
+				{"Name": "alice", "ELO": 1100, "ELOChange": 16},
+				{"Name": "bob", "ELO": 984, "ELOChange": -16}
+			],
+			"Matches": [
+				{"Results": [
+					{"Position": 1, "Player": {"Name": "alice", "ELO": 1016}},
+					{"Position": 2, "Player": {"Name": "bob", "ELO": 984}},
+					{"Position": 3, "Player": {"Name": "carol", "ELO": 968}}
+				], "Date": "2024-01-01T00:00:00Z"}
+			]
+		}`
+
+		l := &multielo.League{}
+		assert.NoError(t, json.Unmarshal([]byte(data), l))
+		assert.Len(t, l.Players, 3)
+
+		carol, err := l.GetPlayer("carol")
+		assert.NoError(t, err)
+		assert.True(t, carol.Retired)
+		assert.Same(t, carol, l.Matches[0].Results[2].Player)
+		assert.Equal(t, 1, carol.Stats.MatchesPlayed)
+
+		// the results are re-rated, but alice keeps her stored rating
+		alice, err := l.GetPlayer("alice")
+		assert.NoError(t, err)
+		assert.Equal(t, multielo.InitialELO, l.Matches[0].Results[0].ELOBefore)
+		assert.Positive(t, l.Matches[0].Results[0].ELOChange)
+		assert.Equal(t, 1100, alice.ELO)
+		assert.Equal(t, 1100, alice.Stats.PeakELO)
+
+		// the log starts from the league as it was read, so it replays
+		log := l.Log()
+		assert.Len(t, log, 2)
+		assert.Equal(t, multielo.OpCreateLeague, log[0].Op)
+		assert.Equal(t, multielo.OpImport, log[1].Op)
+
+		_, err = l.AddMatchByName("bob", "alice")
+		assert.NoError(t, err)
+
+		rebuilt, err := multielo.ReplayLog(l.Log())
+		assert.NoError(t, err)
+		assert.Equal(t, l.Log(), rebuilt.Log())
+		assert.Len(t, rebuilt.Matches, 2)
+		for _, p := range l.Players {
+			replayed, err := rebuilt.GetPlayer(p.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, p.ELO, replayed.ELO)
+			assert.Equal(t, p.Retired, replayed.Retired)
+		}
+
+		// and so does the league once saved in the current schema
+		decoded, _ := roundTrip(t, l)
+		_, err = multielo.ReplayLog(decoded.Log())
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for name, data := range map[string]string{
+			"Version":       `{"version": 99}`,
+			"UnknownPlayer": `{"version": 2, "players": [{"id": 1, "name": "alice"}], "matches": [{"id": 1, "results": [{"player": 1}, {"player": 2}]}]}`,
+			"DuplicateID":   `{"version": 2, "players": [{"id": 1, "name": "alice"}, {"id": 1, "name": "bob"}]}`,
+			"DuplicateName": `{"version": 2, "players": [{"id": 1, "name": "alice"}, {"id": 2, "name": "alice"}]}`,
+			"NotJSON":       `[1, 2, 3]`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				err := json.Unmarshal([]byte(data), &multielo.League{})
+				assert.ErrorIs(t, err, multielo.ErrInvalidLeague)
+			})
+		}
+
+		err := json.Unmarshal([]byte(`{"version": 2, "config": {"k_factor": -1}}`), &multielo.League{})
+		assert.ErrorIs(t, err, multielo.ErrInvalidConfig)
+	})
+}
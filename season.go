@@ -0,0 +1,132 @@
+package multielo
+
+import (
+	"math"
+	"time"
+)
+
+// Season is an archived season: its final standings and every match played
+// in it.
+type Season struct {
+	Name      string
+	Start     time.Time
+	End       time.Time
+	Standings []Standing
+	Matches   []Match
+}
+
+// Standing is a player's final position in a closed season.
+type Standing struct {
+	Rank  int
+	Name  string
+	ELO   int
+	Stats PlayerStats
+}
+
+// CloseSeason archives the current season under name and starts a new one.
+//
+// carryOver controls where everyone starts the new season: each player keeps
+// that fraction of their distance from the league's initial rating. 0 starts
+// everyone afresh on it, 0.5 pulls everyone halfway back and 1 keeps
+// ratings as they are. Stats always start again from zero.
+func (l *League) CloseSeason(name string, carryOver float64) (*Season, error) {
+	return l.closeSeason(name, carryOver, time.Now())
+}
+
+// closeSeason closes the current season at end.
+func (l *League) closeSeason(name string, carryOver float64, end time.Time) (*Season, error) {
+	if name == "" || carryOver < 0 || carryOver > 1 {
+		return nil, ErrInvalidSeason
+	}
+
+	for _, season := range l.Seasons {
+		if season.Name == name {
+			return nil, ErrInvalidSeason
+		}
+	}
+
+	season := Season{
+		Name:      name,
+		Start:     l.SeasonStart,
+		End:       end,
+		Standings: l.standings(end),
+		Matches:   l.Matches,
+	}
+
+	l.Seasons = append(l.Seasons, season)
+	l.Matches = []Match{}
+	l.SeasonStart = season.End
+
+	for _, p := range l.Players {
+		pull := float64(p.ELO-l.Config.initialELO()) * carryOver
+		p.StartingELO = l.Config.initialELO() + int(math.Round(pull))
+		resetPlayer(p)
+	}
+	l.emit(Event{Type: EventLeagueReset})
+	l.record(LogEntry{Op: OpCloseSeason, Time: end, Season: name, CarryOver: carryOver})
+
+	return &l.Seasons[len(l.Seasons)-1], nil
+}
+
+// GetSeason returns the closed season with the given name.
+func (l *League) GetSeason(name string) (*Season, error) {
+	for i := range l.Seasons {
+		if l.Seasons[i].Name == name {
+			return &l.Seasons[i], nil
+		}
+	}
+
+	return nil, ErrSeasonNotFound
+}
+
+// AllMatches returns every match the league has recorded, across closed
+// seasons and the current one, oldest first.
+func (l *League) AllMatches() []Match {
+	matches := []Match{}
+	for _, season := range l.Seasons {
+		matches = append(matches, season.Matches...)
+	}
+
+	return append(matches, l.Matches...)
+}
+
+// SeasonHistory returns the named player's final standing in each closed
+// season they finished ranked in, oldest first.
+func (l *League) SeasonHistory(name string) ([]Standing, error) {
+	p, err := l.GetPlayer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	history := []Standing{}
+	for _, season := range l.Seasons {
+		for _, standing := range season.Standings {
+			if standing.Name == p.Name {
+				history = append(history, standing)
+				break
+			}
+		}
+	}
+
+	return history, nil
+}
+
+// standings snapshots the leaderboard as it stands at now.
+func (l *League) standings(now time.Time) []Standing {
+	leaderboard := l.leaderboard(now)
+	standings := make([]Standing, 0, len(leaderboard))
+
+	for i, p := range leaderboard {
+		stats := *p.Stats
+		stats.Last5Finish = append([]int{}, p.Stats.Last5Finish...)
+
+		standings = append(standings, Standing{
+			Rank:  i + 1,
+			Name:  p.Name,
+			ELO:   p.ELO,
+			Stats: stats,
+		})
+	}
+
+	return standings
+}
@@ -0,0 +1,116 @@
+package multielo_test
+
+import (
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeague_CloseSeason(t *testing.T) {
+	t.Run("CloseSeasonFresh", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob", "carol")
+		recordMatch(t, l, "alice", "bob", "carol")
+		recordMatch(t, l, "alice", "carol", "bob")
+
+		alice, _ := l.GetPlayer("alice")
+		finalELO := alice.ELO
+
+		season, err := l.CloseSeason("january", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "january", season.Name)
+		assert.Len(t, season.Matches, 2)
+		assert.Len(t, season.Standings, 3)
+		assert.Equal(t, 1, season.Standings[0].Rank)
+		assert.Equal(t, "alice", season.Standings[0].Name)
+		assert.Equal(t, finalELO, season.Standings[0].ELO)
+		assert.Equal(t, 2, season.Standings[0].Stats.MatchesWon)
+		assert.False(t, season.End.Before(season.Start))
+
+		assert.Empty(t, l.Matches)
+		assert.Equal(t, season.End, l.SeasonStart)
+		for _, p := range l.Players {
+			assert.Equal(t, multielo.InitialELO, p.ELO)
+			assert.Equal(t, 0, p.Stats.MatchesPlayed)
+		}
+	})
+
+	t.Run("CloseSeasonSoftReset", func(t *testing.T) {
+		l := newTestLeague(t, "alice", "bob")
+		for i := 0; i < 3; i++ {
+			recordMatch(t, l, "alice", "bob")
+		}
+
+		alice, _ := l.GetPlayer("alice")
+		bob, _ := l.GetPlayer("bob")
+		aliceGap := alice.ELO - multielo.InitialELO
+		bobGap := bob.ELO - multielo.InitialELO
+
+		_, err := l.CloseSeason("january", 0.5)
+		assert.NoError(t, err)
+		assert.Equal(t, multielo.InitialELO+aliceGap/2, alice.ELO)
+		assert.Equal(t, multielo.InitialELO+bobGap/2, bob.ELO)
+		assert.Equal(t, alice.ELO, alice.Stats.PeakELO)
+
+		// undoing a match in the new season goes back to the soft-reset rating
+		start := alice.ELO
+		recordMatch(t, l, "bob", "alice")
+		_, err = l.UndoLastMatch()
+		assert.NoError(t, err)
+		assert.Equal(t, start, alice.ELO)
+	})
+
+	t.Run("CloseSeasonInvalid", func(t *testing.T) {
+		l := newTestLeague(t, "alice")
+
+		_, err := l.CloseSeason("", 0)
+		assert.Equal(t, multielo.ErrInvalidSeason, err)
+
+		_, err = l.CloseSeason("january", 1.5)
+		assert.Equal(t, multielo.ErrInvalidSeason, err)
+
+		_, err = l.CloseSeason("january", 0)
+		assert.NoError(t, err)
+
+		_, err = l.CloseSeason("january", 0)
+		assert.Equal(t, multielo.ErrInvalidSeason, err)
+	})
+}
+
+func TestLeague_SeasonQueries(t *testing.T) {
+	l := newTestLeague(t, "alice", "bob", "carol")
+	recordMatch(t, l, "alice", "bob", "carol")
+	_, err := l.CloseSeason("january", 0)
+	assert.NoError(t, err)
+
+	recordMatch(t, l, "bob", "alice")
+	_, err = l.CloseSeason("february", 0)
+	assert.NoError(t, err)
+
+	recordMatch(t, l, "alice", "bob")
+
+	season, err := l.GetSeason("february")
+	assert.NoError(t, err)
+	assert.Len(t, season.Matches, 1)
+
+	_, err = l.GetSeason("march")
+	assert.Equal(t, multielo.ErrSeasonNotFound, err)
+
+	assert.Len(t, l.AllMatches(), 3)
+
+	history, err := l.SeasonHistory("bob")
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, 2, history[0].Rank)
+	assert.Equal(t, 1, history[1].Rank)
+
+	h2h, err := l.HeadToHead("alice", "bob")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, h2h.Matches)
+
+	career, err := l.CareerHeadToHead("alice", "bob")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, career.Matches)
+	assert.Equal(t, 2, career.Wins)
+	assert.Equal(t, 1, career.Losses)
+}
@@ -0,0 +1,520 @@
+// Package server exposes a multielo League over HTTP as a JSON API.
+//
+// Routes:
+//
+//	GET  /players                                list every player
+//	POST /players                                add a player
+//	GET  /players/{name}                         one player
+//	GET  /players/{name}/stats                   a player's stats
+//	GET  /players/{name}/head-to-head/{opponent} a player's record against another
+//	GET  /players/{name}/graph                   a player's rating history, as PNG or
+//	                                             ?format=svg, with ?median=true to
+//	                                             compare it with the league median
+//	GET  /matches                                the current season's matches
+//	POST /matches                                record a match
+//	GET  /leaderboard                            the current standings
+//	GET  /leaderboard/image                      the standings as PNG or ?format=svg
+//	GET  /graph                                  the ELO graph, as PNG or ?format=svg
+//
+// The graph routes also take the query parameters dates=true, players=a,b,
+// top=n, from=2006-01-02, to=2006-01-02, season=name and labels=n, which
+// match multielo's GraphByDate, GraphPlayers and other graph options.
+//
+// Changes are logged against the actor named in the X-Actor header, or the
+// user name of the request's basic auth if there is no header.
+//
+// Errors are sent as an Error with a status code matching the league error
+// that caused them.
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/distrobyte/multielo"
+)
+
+// ErrInvalidRequest is returned for requests the server can't understand.
+var ErrInvalidRequest = errors.New("invalid request")
+
+// ActorHeader names who is making a request, recorded in the league's log
+// against any change it makes.
+const ActorHeader = "X-Actor"
+
+// errorCodes maps league errors to a status code and a short code clients can
+// match on. The first match wins.
+var errorCodes = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{multielo.ErrPlayerNotFound, http.StatusNotFound, "player_not_found"},
+	{multielo.ErrMatchNotFound, http.StatusNotFound, "match_not_found"},
+	{multielo.ErrSeasonNotFound, http.StatusNotFound, "season_not_found"},
+	{multielo.ErrPlayerAlreadyExists, http.StatusConflict, "player_already_exists"},
+	{multielo.ErrPlayerRetired, http.StatusConflict, "player_retired"},
+	{multielo.ErrNoPlayers, http.StatusConflict, "no_players"},
+	{multielo.ErrInvalidMatch, http.StatusBadRequest, "invalid_match"},
+	{multielo.ErrInvalidPlayer, http.StatusBadRequest, "invalid_player"},
+	{multielo.ErrInvalidELOChange, http.StatusBadRequest, "invalid_elo_change"},
+	{multielo.ErrInvalidPlayerStats, http.StatusBadRequest, "invalid_player_stats"},
+	{multielo.ErrInvalidSeason, http.StatusBadRequest, "invalid_season"},
+	{multielo.ErrInvalidConfig, http.StatusBadRequest, "invalid_config"},
+	{multielo.ErrInvalidLeague, http.StatusBadRequest, "invalid_league"},
+	{ErrInvalidRequest, http.StatusBadRequest, "invalid_request"},
+}
+
+// Error is the body of every error response.
+type Error struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// Player is a player as the API sends it.
+type Player struct {
+	Name        string `json:"name"`
+	ELO         int    `json:"elo"`
+	ELOChange   int    `json:"elo_change"`
+	Provisional bool   `json:"provisional"`
+	Retired     bool   `json:"retired"`
+}
+
+// Standing is a player's place on the leaderboard.
+type Standing struct {
+	Rank int `json:"rank"`
+	Player
+}
+
+// Stats is a player's statistics.
+type Stats struct {
+	Name                  string  `json:"name"`
+	MatchesPlayed         int     `json:"matches_played"`
+	MatchesWon            int     `json:"matches_won"`
+	AveragePlace          float64 `json:"average_place"`
+	RecentFinishes        []int   `json:"recent_finishes"`
+	PeakELO               int     `json:"peak_elo"`
+	FormScore             float64 `json:"form_score"`
+	WeightedAverageFinish float64 `json:"weighted_average_finish"`
+	WinStreak             int     `json:"win_streak"`
+	LongestWinStreak      int     `json:"longest_win_streak"`
+	PodiumStreak          int     `json:"podium_streak"`
+	LongestPodiumStreak   int     `json:"longest_podium_streak"`
+}
+
+// HeadToHead is one player's record against another.
+type HeadToHead struct {
+	Player             string  `json:"player"`
+	Opponent           string  `json:"opponent"`
+	Matches            int     `json:"matches"`
+	Wins               int     `json:"wins"`
+	Losses             int     `json:"losses"`
+	Ties               int     `json:"ties"`
+	AveragePositionGap float64 `json:"average_position_gap"`
+	RatingExchanged    int     `json:"rating_exchanged"`
+}
+
+// Match is a recorded match.
+type Match struct {
+	ID      int       `json:"id"`
+	Date    time.Time `json:"date"`
+	Results []Result  `json:"results"`
+}
+
+// Result is one player's finish in a match.
+type Result struct {
+	Player    string `json:"player"`
+	Position  int    `json:"position"`
+	ELOBefore int    `json:"elo_before"`
+	ELOChange int    `json:"elo_change"`
+}
+
+// Diff is how much a match moved a player's rating.
+type Diff struct {
+	Player string `json:"player"`
+	ELO    int    `json:"elo"`
+	Diff   int    `json:"diff"`
+}
+
+// CreatePlayerRequest is the body of POST /players. ELO defaults to the
+// league's starting rating.
+type CreatePlayerRequest struct {
+	Name string `json:"name"`
+	ELO  *int   `json:"elo,omitempty"`
+}
+
+// MatchRequest is the body of POST /matches. Either Players lists the
+// players in finishing order, winner first, or Places lists the players in
+// each finishing place, so players sharing a place tied.
+type MatchRequest struct {
+	Players []string   `json:"players,omitempty"`
+	Places  [][]string `json:"places,omitempty"`
+}
+
+// Server serves a league over HTTP. Requests are handled one at a time, as a
+// League isn't safe for concurrent use.
+type Server struct {
+	mu     sync.Mutex
+	league *multielo.League
+	mux    *http.ServeMux
+}
+
+// New returns a server for league.
+func New(league *multielo.League) *Server {
+	s := &Server{league: league, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /players", s.listPlayers)
+	s.mux.HandleFunc("POST /players", s.createPlayer)
+	s.mux.HandleFunc("GET /players/{name}", s.getPlayer)
+	s.mux.HandleFunc("GET /players/{name}/stats", s.getStats)
+	s.mux.HandleFunc("GET /players/{name}/head-to-head/{opponent}", s.getHeadToHead)
+	s.mux.HandleFunc("GET /players/{name}/graph", s.getPlayerGraph)
+	s.mux.HandleFunc("GET /matches", s.listMatches)
+	s.mux.HandleFunc("POST /matches", s.createMatch)
+	s.mux.HandleFunc("GET /leaderboard", s.getLeaderboard)
+	s.mux.HandleFunc("GET /leaderboard/image", s.getLeaderboardImage)
+	s.mux.HandleFunc("GET /graph", s.getGraph)
+
+	return s
+}
+
+// ServeHTTP handles a request against the server's league.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.league.SetActor(actor(r))
+	defer s.league.SetActor("")
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// actor returns who is making a request, from ActorHeader or basic auth.
+func actor(r *http.Request) string {
+	if actor := strings.TrimSpace(r.Header.Get(ActorHeader)); actor != "" {
+		return actor
+	}
+
+	user, _, _ := r.BasicAuth()
+	return user
+}
+
+func (s *Server) listPlayers(w http.ResponseWriter, r *http.Request) {
+	players := []Player{}
+	for _, p := range s.league.GetPlayers() {
+		players = append(players, s.player(p))
+	}
+
+	writeJSON(w, http.StatusOK, players)
+}
+
+func (s *Server) createPlayer(w http.ResponseWriter, r *http.Request) {
+	var req CreatePlayerRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if req.Name == "" {
+		writeError(w, fmt.Errorf("%w: name is required", multielo.ErrInvalidPlayer))
+		return
+	}
+
+	var err error
+	if req.ELO != nil {
+		err = s.league.AddPlayerWithRating(req.Name, *req.ELO)
+	} else {
+		err = s.league.AddPlayer(req.Name)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	p, err := s.league.GetPlayer(req.Name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s.player(p))
+}
+
+func (s *Server) getPlayer(w http.ResponseWriter, r *http.Request) {
+	p, err := s.league.GetPlayer(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.player(p))
+}
+
+func (s *Server) getStats(w http.ResponseWriter, r *http.Request) {
+	p, err := s.league.GetPlayer(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	stats := Stats{
+		Name:                  p.Name,
+		MatchesPlayed:         p.Stats.MatchesPlayed,
+		MatchesWon:            p.Stats.MatchesWon,
+		RecentFinishes:        append([]int{}, p.Stats.Last5Finish...),
+		PeakELO:               p.Stats.PeakELO,
+		FormScore:             p.Stats.FormScore,
+		WeightedAverageFinish: p.Stats.WeightedAverageFinish,
+		WinStreak:             p.Stats.WinStreak,
+		LongestWinStreak:      p.Stats.LongestWinStreak,
+		PodiumStreak:          p.Stats.PodiumStreak,
+		LongestPodiumStreak:   p.Stats.LongestPodiumStreak,
+		AveragePlace:          p.Stats.AveragePlace(),
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func (s *Server) getHeadToHead(w http.ResponseWriter, r *http.Request) {
+	h2h, err := s.league.HeadToHead(r.PathValue("name"), r.PathValue("opponent"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, HeadToHead{
+		Player:             h2h.PlayerA,
+		Opponent:           h2h.PlayerB,
+		Matches:            h2h.Matches,
+		Wins:               h2h.Wins,
+		Losses:             h2h.Losses,
+		Ties:               h2h.Ties,
+		AveragePositionGap: h2h.AveragePositionGap,
+		RatingExchanged:    h2h.RatingExchanged,
+	})
+}
+
+func (s *Server) listMatches(w http.ResponseWriter, r *http.Request) {
+	matches := []Match{}
+	for _, match := range s.league.GetMatches() {
+		m := Match{ID: match.ID, Date: match.Date, Results: []Result{}}
+		for _, result := range match.Results {
+			if result.Player == nil {
+				continue
+			}
+
+			m.Results = append(m.Results, Result{
+				Player:    result.Player.Name,
+				Position:  result.Position,
+				ELOBefore: result.ELOBefore,
+				ELOChange: result.ELOChange,
+			})
+		}
+		matches = append(matches, m)
+	}
+
+	writeJSON(w, http.StatusOK, matches)
+}
+
+func (s *Server) createMatch(w http.ResponseWriter, r *http.Request) {
+	var req MatchRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var diffs []multielo.MatchDiff
+	var err error
+	switch {
+	case req.Players != nil && req.Places != nil:
+		err = fmt.Errorf("%w: send players or places, not both", ErrInvalidRequest)
+	case req.Places != nil:
+		diffs, err = s.league.AddMatchPlaces(req.Places)
+	default:
+		diffs, err = s.league.AddMatchByName(req.Players...)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	out := make([]Diff, 0, len(diffs))
+	for _, diff := range diffs {
+		out = append(out, Diff{Player: diff.Player.Name, ELO: diff.Player.ELO, Diff: diff.Diff})
+	}
+
+	writeJSON(w, http.StatusCreated, out)
+}
+
+func (s *Server) getLeaderboard(w http.ResponseWriter, r *http.Request) {
+	standings := []Standing{}
+	for i, p := range s.league.Leaderboard() {
+		standings = append(standings, Standing{Rank: i + 1, Player: s.player(p)})
+	}
+
+	writeJSON(w, http.StatusOK, standings)
+}
+
+func (s *Server) getLeaderboardImage(w http.ResponseWriter, r *http.Request) {
+	writeImage(w, r, s.league.WriteLeaderboardImage)
+}
+
+func (s *Server) getGraph(w http.ResponseWriter, r *http.Request) {
+	opts, err := graphOptions(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeImage(w, r, func(w io.Writer, format string) error {
+		return s.league.WriteGraph(w, format, opts...)
+	})
+}
+
+func (s *Server) getPlayerGraph(w http.ResponseWriter, r *http.Request) {
+	opts, err := graphOptions(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeImage(w, r, func(w io.Writer, format string) error {
+		return s.league.WritePlayerGraph(w, format, r.PathValue("name"), opts...)
+	})
+}
+
+// graphOptions reads the graph options in the request's query.
+func graphOptions(r *http.Request) ([]multielo.GraphOption, error) {
+	query := r.URL.Query()
+	opts := []multielo.GraphOption{}
+
+	for name, option := range map[string]func() multielo.GraphOption{
+		"median": multielo.GraphMedian,
+		"dates":  multielo.GraphByDate,
+	} {
+		if value := query.Get(name); value != "" {
+			on, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s must be true or false", ErrInvalidRequest, name)
+			}
+			if on {
+				opts = append(opts, option())
+			}
+		}
+	}
+
+	for name, option := range map[string]func(int) multielo.GraphOption{
+		"top":    multielo.GraphTop,
+		"labels": multielo.GraphLabelEvery,
+	} {
+		if value := query.Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%w: %s must be a whole number", ErrInvalidRequest, name)
+			}
+			opts = append(opts, option(n))
+		}
+	}
+
+	if players := query.Get("players"); players != "" {
+		opts = append(opts, multielo.GraphPlayers(strings.Split(players, ",")...))
+	}
+
+	if season := query.Get("season"); season != "" {
+		opts = append(opts, multielo.GraphSeason(season))
+	}
+
+	var from, to time.Time
+	for name, date := range map[string]*time.Time{"from": &from, "to": &to} {
+		if value := query.Get(name); value != "" {
+			var err error
+			if *date, err = time.Parse(time.DateOnly, value); err != nil {
+				return nil, fmt.Errorf("%w: %s must be a date such as 2006-01-02", ErrInvalidRequest, name)
+			}
+		}
+	}
+	if !from.IsZero() || !to.IsZero() {
+		opts = append(opts, multielo.GraphBetween(from, to))
+	}
+
+	return opts, nil
+}
+
+// writeImage sends the image drawn by draw in the format asked for by the
+// request, PNG by default.
+func writeImage(w http.ResponseWriter, r *http.Request, draw func(w io.Writer, format string) error) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "png"
+	}
+
+	contentTypes := map[string]string{
+		"png": "image/png",
+		"svg": "image/svg+xml",
+	}
+
+	contentType, ok := contentTypes[format]
+	if !ok {
+		writeError(w, fmt.Errorf("%w: unsupported image format %q", ErrInvalidRequest, format))
+		return
+	}
+
+	// draw the whole image first so a failure can still be sent as JSON
+	var buf bytes.Buffer
+	if err := draw(&buf, format); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	buf.WriteTo(w)
+}
+
+// player converts a league player for the API.
+func (s *Server) player(p *multielo.Player) Player {
+	return Player{
+		Name:        p.Name,
+		ELO:         p.ELO,
+		ELOChange:   p.ELOChange,
+		Provisional: s.league.IsProvisional(p),
+		Retired:     p.Retired,
+	}
+}
+
+// readJSON decodes a request body into v, rejecting unknown fields.
+func readJSON(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError sends err as an Error, with a status code matching the league
+// error behind it.
+func writeError(w http.ResponseWriter, err error) {
+	for _, e := range errorCodes {
+		if errors.Is(err, e.err) {
+			writeJSON(w, e.status, Error{Error: err.Error(), Code: e.code})
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusInternalServerError, Error{Error: err.Error(), Code: "internal"})
+}
@@ -0,0 +1,281 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/distrobyte/multielo"
+	"github.com/distrobyte/multielo/server"
+	"github.com/stretchr/testify/assert"
+)
+
+// do sends a request to s and decodes the JSON response into out, if given.
+func do(t *testing.T, s *server.Server, method, path, body string, out any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	if out != nil {
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+	}
+
+	return rec
+}
+
+func newTestServer(t *testing.T, names ...string) (*server.Server, *multielo.League) {
+	t.Helper()
+
+	l := multielo.NewLeague()
+	for _, name := range names {
+		assert.NoError(t, l.AddPlayer(name))
+	}
+
+	return server.New(l), l
+}
+
+func TestServer_Players(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		s, l := newTestServer(t)
+
+		var player server.Player
+		rec := do(t, s, http.MethodPost, "/players", `{"name": "Alice"}`, &player)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, server.Player{Name: "alice", ELO: multielo.InitialELO}, player)
+
+		rec = do(t, s, http.MethodPost, "/players", `{"name": "bob", "elo": 1200}`, &player)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, 1200, player.ELO)
+		assert.Len(t, l.Players, 2)
+
+		var players []server.Player
+		rec = do(t, s, http.MethodGet, "/players", "", &players)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{"alice", "bob"}, []string{players[0].Name, players[1].Name})
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		s, _ := newTestServer(t, "alice")
+
+		var e server.Error
+		rec := do(t, s, http.MethodPost, "/players", `{"name": "alice"}`, &e)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "player_already_exists", e.Code)
+
+		rec = do(t, s, http.MethodPost, "/players", `{"name": ""}`, &e)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_player", e.Code)
+
+		rec = do(t, s, http.MethodPost, "/players", `{"name": "bob", "elo": -5}`, &e)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_player", e.Code)
+
+		rec = do(t, s, http.MethodPost, "/players", `{"nmae": "bob"}`, &e)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_request", e.Code)
+
+		rec = do(t, s, http.MethodGet, "/players/bob", "", &e)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "player_not_found", e.Code)
+		assert.Equal(t, multielo.ErrPlayerNotFound.Error(), e.Error)
+	})
+}
+
+func TestServer_Matches(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		s, l := newTestServer(t, "alice", "bob", "carol")
+
+		var diffs []server.Diff
+		rec := do(t, s, http.MethodPost, "/matches", `{"players": ["alice", "bob"]}`, &diffs)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, []server.Diff{
+			{Player: "alice", ELO: 1016, Diff: 16},
+			{Player: "bob", ELO: 985, Diff: -15},
+		}, diffs)
+
+		rec = do(t, s, http.MethodPost, "/matches", `{"places": [["carol"], ["alice", "bob"]]}`, &diffs)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Len(t, diffs, 3)
+		assert.Len(t, l.Matches, 2)
+
+		var matches []server.Match
+		rec = do(t, s, http.MethodGet, "/matches", "", &matches)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, matches, 2)
+		assert.Equal(t, l.Matches[1].ID, matches[1].ID)
+		assert.Equal(t, server.Result{Player: "bob", Position: 2, ELOBefore: 985, ELOChange: l.Matches[1].Results[2].ELOChange}, matches[1].Results[2])
+
+		var standings []server.Standing
+		rec = do(t, s, http.MethodGet, "/leaderboard", "", &standings)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, standings, 3)
+		assert.Equal(t, 1, standings[0].Rank)
+		assert.Equal(t, l.Leaderboard()[0].Name, standings[0].Name)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		s, l := newTestServer(t, "alice", "bob")
+		assert.NoError(t, l.RemovePlayer("bob"))
+
+		var e server.Error
+		rec := do(t, s, http.MethodPost, "/matches", `{"players": ["alice", "mallory"]}`, &e)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "player_not_found", e.Code)
+
+		rec = do(t, s, http.MethodPost, "/matches", `{"players": ["alice", "bob"]}`, &e)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "player_retired", e.Code)
+
+		rec = do(t, s, http.MethodPost, "/matches", `{"players": ["alice"]}`, &e)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_match", e.Code)
+
+		rec = do(t, s, http.MethodPost, "/matches", `{"players": ["alice", "bob"], "places": [["alice"], ["bob"]]}`, &e)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_request", e.Code)
+
+		s, _ = newTestServer(t)
+		rec = do(t, s, http.MethodPost, "/matches", `{"players": ["alice", "bob"]}`, &e)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "no_players", e.Code)
+	})
+}
+
+func TestServer_Actor(t *testing.T) {
+	s, l := newTestServer(t, "alice")
+
+	req := httptest.NewRequest(http.MethodPost, "/players", strings.NewReader(`{"name": "bob"}`))
+	req.Header.Set(server.ActorHeader, "carol")
+	s.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodPost, "/matches", strings.NewReader(`{"players": ["alice", "bob"]}`))
+	req.SetBasicAuth("dave", "secret")
+	s.ServeHTTP(httptest.NewRecorder(), req)
+
+	do(t, s, http.MethodPost, "/players", `{"name": "erin"}`, nil)
+
+	log := l.Log()
+	assert.Len(t, log, 5)
+	assert.Equal(t, "carol", log[2].Actor)
+	assert.Equal(t, "dave", log[3].Actor)
+	assert.Equal(t, multielo.OpAddMatch, log[3].Op)
+	assert.Equal(t, "", log[4].Actor)
+}
+
+func TestServer_PlayerStats(t *testing.T) {
+	s, l := newTestServer(t, "alice", "bob", "carol")
+	_, err := l.AddMatchByName("alice", "bob", "carol")
+	assert.NoError(t, err)
+	_, err = l.AddMatchByName("bob", "alice", "carol")
+	assert.NoError(t, err)
+
+	var stats server.Stats
+	rec := do(t, s, http.MethodGet, "/players/Alice/stats", "", &stats)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "alice", stats.Name)
+	assert.Equal(t, 2, stats.MatchesPlayed)
+	assert.Equal(t, 1, stats.MatchesWon)
+	assert.Equal(t, 1.5, stats.AveragePlace)
+	assert.Equal(t, []int{1, 2}, stats.RecentFinishes)
+
+	var h2h server.HeadToHead
+	rec = do(t, s, http.MethodGet, "/players/alice/head-to-head/bob", "", &h2h)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "bob", h2h.Opponent)
+	assert.Equal(t, 2, h2h.Matches)
+	assert.Equal(t, 1, h2h.Wins)
+	assert.Equal(t, 1, h2h.Losses)
+
+	var e server.Error
+	rec = do(t, s, http.MethodGet, "/players/alice/head-to-head/alice", "", &e)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "invalid_player", e.Code)
+}
+
+func TestServer_Graph(t *testing.T) {
+	s, l := newTestServer(t, "alice", "bob")
+	_, err := l.AddMatchByName("alice", "bob")
+	assert.NoError(t, err)
+
+	rec := do(t, s, http.MethodGet, "/graph", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "\x89PNG"))
+
+	rec = do(t, s, http.MethodGet, "/graph?format=svg", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/svg+xml", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "<svg")
+
+	rec = do(t, s, http.MethodGet, "/graph?format=svg&top=1&dates=true&labels=0", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "alice (")
+	assert.NotContains(t, rec.Body.String(), "bob (")
+
+	var e server.Error
+	rec = do(t, s, http.MethodGet, "/graph?format=bmp", "", &e)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	for _, query := range []string{"top=three", "labels=-1", "dates=maybe", "from=yesterday"} {
+		rec = do(t, s, http.MethodGet, "/graph?"+query, "", &e)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+
+	rec = do(t, s, http.MethodGet, "/graph?season=spring", "", &e)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "season_not_found", e.Code)
+
+	s, _ = newTestServer(t)
+	rec = do(t, s, http.MethodGet, "/graph", "", &e)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "no_players", e.Code)
+}
+
+func TestServer_PlayerGraph(t *testing.T) {
+	s, l := newTestServer(t, "alice", "bob")
+	_, err := l.AddMatchByName("alice", "bob")
+	assert.NoError(t, err)
+
+	rec := do(t, s, http.MethodGet, "/players/alice/graph", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+
+	rec = do(t, s, http.MethodGet, "/players/alice/graph?format=svg&median=true", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "League median")
+
+	var e server.Error
+	rec = do(t, s, http.MethodGet, "/players/alice/graph?median=maybe", "", &e)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "invalid_request", e.Code)
+
+	rec = do(t, s, http.MethodGet, "/players/mallory/graph", "", &e)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "player_not_found", e.Code)
+}
+
+func TestServer_LeaderboardImage(t *testing.T) {
+	s, l := newTestServer(t, "alice", "bob")
+	_, err := l.AddMatchByName("alice", "bob")
+	assert.NoError(t, err)
+
+	rec := do(t, s, http.MethodGet, "/leaderboard/image", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "\x89PNG"))
+
+	rec = do(t, s, http.MethodGet, "/leaderboard/image?format=svg", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "alice")
+
+	var e server.Error
+	s, _ = newTestServer(t)
+	rec = do(t, s, http.MethodGet, "/leaderboard/image", "", &e)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "no_players", e.Code)
+}